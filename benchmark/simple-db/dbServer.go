@@ -1,14 +1,34 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net"
 
+	"github.com/DCsunset/openwhisk-grpc/latency"
 	simpleDb "github.com/DCsunset/openwhisk-grpc/simple-db"
 	"google.golang.org/grpc"
 )
 
 func main() {
+	// Use the same latency as the db server for a fair comparison
+	configFile := flag.String("config", "", "read latency from this configuration file of the db server")
+	var config latency.Config
+	config.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	if len(*configFile) > 0 {
+		var err error
+		if config, err = latency.Load(*configFile); err != nil {
+			log.Fatalln(err)
+		}
+		// Parse again so that flags override the configuration file
+		flag.Parse()
+	}
+	injector, err := latency.New(config)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
 	lis, err := net.Listen("tcp", ":9001")
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
@@ -16,7 +36,7 @@ func main() {
 
 	log.Println("Server listen at :9001")
 
-	server := Server{}
+	server := Server{latency: injector}
 	grpcServer := grpc.NewServer()
 	simpleDb.RegisterDbServiceServer(grpcServer, &server)
 
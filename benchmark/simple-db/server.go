@@ -4,15 +4,16 @@ import (
 	"context"
 	"fmt"
 	"sync"
-	"time"
 
+	"github.com/DCsunset/openwhisk-grpc/latency"
 	simpleDb "github.com/DCsunset/openwhisk-grpc/simple-db"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type Server struct {
-	lock sync.RWMutex
+	lock    sync.RWMutex
+	latency *latency.Injector
 }
 
 var store = make(map[string]string)
@@ -20,8 +21,7 @@ var store = make(map[string]string)
 func (s *Server) Get(ctx context.Context, in *simpleDb.GetRequest) (*simpleDb.GetResponse, error) {
 	fmt.Printf("Get %s\n", in.Key)
 
-	// FIXME: Similuate disk
-	time.Sleep(time.Millisecond * 10)
+	s.latency.Get()
 
 	value, ok := store[in.Key]
 	if ok {
@@ -36,8 +36,7 @@ func (s *Server) Set(ctx context.Context, in *simpleDb.SetRequest) (*simpleDb.Se
 
 	fmt.Printf("Set %s\n", in.Key)
 
-	// FIXME: Similuate disk
-	time.Sleep(time.Millisecond * 10)
+	s.latency.Set()
 
 	store[in.Key] = in.Value
 	return &simpleDb.SetResponse{}, nil
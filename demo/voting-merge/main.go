@@ -39,6 +39,7 @@ func main() {
 	ctx := context.Background()
 
 	var children []*db.Node
+	var deletes []*db.Node
 	votesNum := 0
 	maxVotes := 0
 	for _, child := range parent.Children {
@@ -49,7 +50,9 @@ func main() {
 			log.Fatalln(err)
 		}
 
-		if node.Key == "votes" {
+		if node.Key == "votes" && node.Deleted {
+			deletes = append(deletes, node)
+		} else if node.Key == "votes" {
 			votesNum += 1
 			if maxVotes < utils.Str2Int(node.Value) {
 				maxVotes = utils.Str2Int(node.Value)
@@ -59,11 +62,16 @@ func main() {
 			children = append(children, node)
 		}
 	}
-	children = append(children, storage.CreateNode(
-		"votes",
-		strconv.Itoa(maxVotes+votesNum-1),
-		parent.Location,
-	))
+	if votesNum > 0 {
+		// Updates win over deletes
+		children = append(children, storage.CreateNode(
+			"votes",
+			strconv.Itoa(maxVotes+votesNum-1),
+			parent.Location,
+		))
+	} else {
+		children = append(children, deletes...)
+	}
 
 	utils.Print(db.Nodes{
 		Nodes: children,
@@ -1,7 +1,11 @@
 package indexing
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
 
 	"github.com/DCsunset/openwhisk-grpc/utils"
 )
@@ -12,20 +16,96 @@ type Mapping struct {
 	Address string
 }
 
+// Routing table replaced as a whole, never modified once in use
+type Table struct {
+	Partitioner
+	// Increased by every split so that stale routing can be detected
+	Epoch uint64
+}
+
 type Service struct {
+	// Current *Table, loaded once per request
+	table   atomic.Value
+	mutex   sync.Mutex
+	changed chan struct{}
+	locked  bool
+}
+
+func (self *Service) Init(p Partitioner) {
+	self.changed = make(chan struct{})
+	self.table.Store(&Table{Partitioner: p})
+}
+
+func (self *Service) Table() *Table {
+	return self.table.Load().(*Table)
+}
+
+// Route with table from now on
+func (self *Service) SetTable(table *Table) {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.table.Store(table)
+	close(self.changed)
+	self.changed = make(chan struct{})
+}
+
+// Wait till the table in use reaches epoch
+func (self *Service) Wait(ctx context.Context, epoch uint64) error {
+	for {
+		self.mutex.Lock()
+		if self.Table().Epoch >= epoch {
+			self.mutex.Unlock()
+			return nil
+		}
+		changed := self.changed
+		self.mutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Acquire the indexing lock and return whether it was free
+func (self *Service) TryLock() bool {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	if self.locked {
+		return false
+	}
+	self.locked = true
+	return true
+}
+
+func (self *Service) Unlock() {
+	self.mutex.Lock()
+	defer self.mutex.Unlock()
+	self.locked = false
+}
+
+func KeyHash(key string) uint32 {
+	return utils.Hash2Uint(utils.Hash([]byte(key)))
+}
+
+// Ranges of key hashes split in half when a server is full
+type RangePartitioner struct {
 	Mappings []Mapping
-	Lock     bool
 }
 
-func (self *Service) Init() {
-	self.Lock = false
+// Use initial server first
+func NewRangePartitioner(initial string) *RangePartitioner {
+	p := &RangePartitioner{}
+	p.AddMapping(0, math.MaxUint32, initial)
+	return p
 }
 
-func (s *Service) AddMapping(left, right uint32, server string) {
+func (s *RangePartitioner) AddMapping(left, right uint32, server string) {
 	s.Mappings = append(s.Mappings, Mapping{left, right, server})
 }
 
-func (s *Service) RemoveMapping(left, right uint32) {
+func (s *RangePartitioner) RemoveMapping(left, right uint32) {
 	for i, mapping := range s.Mappings {
 		if mapping.Left == left && mapping.Right == right {
 			l := len(s.Mappings)
@@ -36,7 +116,7 @@ func (s *Service) RemoveMapping(left, right uint32) {
 	}
 }
 
-func (self *Service) Locate(keyHash uint32) string {
+func (self *RangePartitioner) LocateHash(keyHash uint32) string {
 	for _, m := range self.Mappings {
 		if keyHash >= m.Left && keyHash <= m.Right {
 			return m.Address
@@ -45,21 +125,79 @@ func (self *Service) Locate(keyHash uint32) string {
 	panic(fmt.Sprintf("Key hash %x not found", keyHash))
 }
 
-func (s *Service) LocateKey(key string) string {
-	keyHash := utils.Hash2Uint(utils.Hash([]byte(key)))
-	return s.Locate(keyHash)
+func (s *RangePartitioner) Owner(key string, loc uint64) string {
+	return s.LocateHash(utils.KeyHash(loc))
+}
+
+func (s *RangePartitioner) Owners(loc uint64) []string {
+	return []string{s.LocateHash(utils.KeyHash(loc))}
 }
 
-func (s *Service) Range(server string) (uint32, uint32) {
+func (s *RangePartitioner) KeyHash(key string) uint32 {
+	return KeyHash(key)
+}
+
+func (s *RangePartitioner) LocateKey(key string) string {
+	return s.LocateHash(KeyHash(key))
+}
+
+func (s *RangePartitioner) Ranges(server string) []Mapping {
+	var ranges []Mapping
 	for _, mapping := range s.Mappings {
 		if mapping.Address == server {
-			return mapping.Left, mapping.Right
+			ranges = append(ranges, mapping)
+		}
+	}
+	return ranges
+}
+
+// Keys are spread over all hashes
+func (s *RangePartitioner) ScanRange(startKey, endKey string) (uint32, uint32) {
+	return 0, math.MaxUint32
+}
+
+// Split the range of server in the middle and move the smaller half
+func (s *RangePartitioner) ProposeSplit(server, target string, samples []Sample) (Split, bool) {
+	ranges := s.Ranges(server)
+	if len(ranges) == 0 || ranges[0].Left == ranges[0].Right {
+		return Split{}, false
+	}
+	left, right := ranges[0].Left, ranges[0].Right
+	mid := uint32((uint64(left) + uint64(right)) / 2)
+
+	le := 0
+	greater := 0
+	for _, sample := range samples {
+		if sample.KeyHash > mid {
+			greater += 1
+		} else if sample.KeyHash >= left {
+			le += 1
 		}
 	}
-	return 0, 0
+
+	split := Split{Left: left, Right: right, Mid: mid}
+	if greater >= le {
+		split.LeftServer, split.RightServer = target, server
+	} else {
+		split.LeftServer, split.RightServer = server, target
+	}
+	return split, true
+}
+
+// [l, m] [m+1, r]
+func (s *RangePartitioner) ApplySplit(split Split) {
+	s.RemoveMapping(split.Left, split.Right)
+	s.AddMapping(split.Left, split.Mid, split.LeftServer)
+	s.AddMapping(split.Mid+1, split.Right, split.RightServer)
+}
+
+func (s *RangePartitioner) Clone() Partitioner {
+	return &RangePartitioner{
+		Mappings: append([]Mapping(nil), s.Mappings...),
+	}
 }
 
-func (s *Service) Print() {
+func (s *RangePartitioner) Print() {
 	fmt.Println("Mappings:")
 	for _, m := range s.Mappings {
 		fmt.Printf("%x-%x: %s\n", m.Left, m.Right, m.Address)
@@ -0,0 +1,186 @@
+package indexing
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Start of a range of keys owned by a server, which ends at the next start
+type Bound struct {
+	Start   string
+	Address string
+}
+
+// Ranges of raw keys so that adjacent keys are on the same or adjacent servers.
+// Ranges end at key strings, so keys sharing their first 4 bytes can be split
+// and nodes at locations with the key hash of a boundary may be on either side.
+type OrderedPartitioner struct {
+	// Sorted by start, the first one starting at the empty key
+	Bounds []Bound
+}
+
+func NewOrderedPartitioner(initial string) *OrderedPartitioner {
+	return &OrderedPartitioner{
+		Bounds: []Bound{{Start: "", Address: initial}},
+	}
+}
+
+// First 4 bytes of key padded with zeros, which preserves the order of keys
+func PrefixHash(key string) uint32 {
+	var prefix [4]byte
+	copy(prefix[:], key)
+	return binary.BigEndian.Uint32(prefix[:])
+}
+
+// Smallest key of keyHash
+func firstKey(keyHash uint32) string {
+	var prefix [4]byte
+	binary.BigEndian.PutUint32(prefix[:], keyHash)
+	return strings.TrimRight(string(prefix[:]), "\x00")
+}
+
+// Largest key hash of keys before end
+func hashBefore(end string) uint32 {
+	keyHash := PrefixHash(end)
+	if end == firstKey(keyHash) {
+		return keyHash - 1
+	}
+	return keyHash
+}
+
+// Index of the bound of the range containing key
+func (s *OrderedPartitioner) find(key string) int {
+	return sort.Search(len(s.Bounds), func(i int) bool {
+		return s.Bounds[i].Start > key
+	}) - 1
+}
+
+func (s *OrderedPartitioner) KeyHash(key string) uint32 {
+	return PrefixHash(key)
+}
+
+func (s *OrderedPartitioner) LocateKey(key string) string {
+	return s.Bounds[s.find(key)].Address
+}
+
+func (s *OrderedPartitioner) Owner(key string, loc uint64) string {
+	return s.LocateKey(key)
+}
+
+// Owners of the ranges with keys of the key hash of loc
+func (s *OrderedPartitioner) Owners(loc uint64) []string {
+	keyHash := uint32(loc >> 32)
+	var owners []string
+	for i := s.find(firstKey(keyHash)); i < len(s.Bounds); i++ {
+		if keyHash < math.MaxUint32 && s.Bounds[i].Start >= firstKey(keyHash+1) {
+			break
+		}
+		address := s.Bounds[i].Address
+		found := false
+		for _, owner := range owners {
+			found = found || owner == address
+		}
+		if !found {
+			owners = append(owners, address)
+		}
+	}
+	return owners
+}
+
+func (s *OrderedPartitioner) Ranges(server string) []Mapping {
+	var ranges []Mapping
+	for i, bound := range s.Bounds {
+		if bound.Address != server {
+			continue
+		}
+		mapping := Mapping{Left: PrefixHash(bound.Start), Right: math.MaxUint32, Address: server}
+		if i+1 < len(s.Bounds) {
+			mapping.Right = hashBefore(s.Bounds[i+1].Start)
+		}
+		ranges = append(ranges, mapping)
+	}
+	return ranges
+}
+
+func (s *OrderedPartitioner) ScanRange(startKey, endKey string) (uint32, uint32) {
+	if len(endKey) == 0 {
+		return PrefixHash(startKey), math.MaxUint32
+	}
+	return PrefixHash(startKey), hashBefore(endKey)
+}
+
+// Split the range of server at the median key so that both halves have similar size
+func (s *OrderedPartitioner) ProposeSplit(server, target string, samples []Sample) (Split, bool) {
+	// First range of server like the other partitioners
+	first := -1
+	for i := len(s.Bounds) - 1; i >= 0; i-- {
+		if s.Bounds[i].Address == server {
+			first = i
+		}
+	}
+	if first < 0 {
+		return Split{}, false
+	}
+	var keys []string
+	for _, sample := range samples {
+		if s.find(sample.Key) == first {
+			keys = append(keys, sample.Key)
+		}
+	}
+	if len(keys) == 0 {
+		return Split{}, false
+	}
+	sort.Strings(keys)
+
+	// Keep versions of the same key on one side
+	mid := keys[len(keys)/2]
+	if mid == keys[0] {
+		i := sort.Search(len(keys), func(i int) bool {
+			return keys[i] > mid
+		})
+		if i == len(keys) {
+			// All nodes are of the same key
+			return Split{}, false
+		}
+		mid = keys[i]
+	}
+	lower := sort.SearchStrings(keys, mid)
+
+	split := Split{Key: mid}
+	// Move the smaller half
+	if len(keys)-lower >= lower {
+		split.LeftServer, split.RightServer = target, server
+	} else {
+		split.LeftServer, split.RightServer = server, target
+	}
+	return split, true
+}
+
+// [start, Key) to LeftServer and [Key, end) to RightServer
+func (s *OrderedPartitioner) ApplySplit(split Split) {
+	i := s.find(split.Key)
+	s.Bounds[i].Address = split.LeftServer
+	if s.Bounds[i].Start == split.Key {
+		s.Bounds[i].Address = split.RightServer
+		return
+	}
+	s.Bounds = append(s.Bounds, Bound{})
+	copy(s.Bounds[i+2:], s.Bounds[i+1:])
+	s.Bounds[i+1] = Bound{Start: split.Key, Address: split.RightServer}
+}
+
+func (s *OrderedPartitioner) Clone() Partitioner {
+	return &OrderedPartitioner{
+		Bounds: append([]Bound(nil), s.Bounds...),
+	}
+}
+
+func (s *OrderedPartitioner) Print() {
+	fmt.Println("Bounds:")
+	for _, b := range s.Bounds {
+		fmt.Printf("%q: %s\n", b.Start, b.Address)
+	}
+}
@@ -0,0 +1,38 @@
+package indexing
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedSplitKeysWithSamePrefix(t *testing.T) {
+	p := NewOrderedPartitioner("a")
+	var samples []Sample
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("user:%d", i)
+		samples = append(samples, Sample{Key: key, KeyHash: p.KeyHash(key)})
+	}
+
+	split, ok := p.ProposeSplit("a", "b", samples)
+	if !ok {
+		t.Fatalf("Keys sharing their first 4 bytes are not split")
+	}
+	if split.Key != "user:5" {
+		t.Fatalf("Split at %q instead of the median key", split.Key)
+	}
+	p.ApplySplit(split)
+
+	lower, upper := p.LocateKey("user:4"), p.LocateKey("user:5")
+	if lower == upper {
+		t.Fatalf("Both halves are on %s", lower)
+	}
+	// Nodes of both halves have the same key hash
+	loc := uint64(p.KeyHash("user:4"))<<32 | 1
+	if owners := p.Owners(loc); !reflect.DeepEqual(owners, []string{lower, upper}) {
+		t.Fatalf("Owners of %x are %v instead of %v", loc, owners, []string{lower, upper})
+	}
+	if owners := p.Owners(uint64(p.KeyHash("zzzz")) << 32); !reflect.DeepEqual(owners, []string{upper}) {
+		t.Fatalf("Owners of keys after the split are %v", owners)
+	}
+}
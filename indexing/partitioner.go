@@ -0,0 +1,58 @@
+package indexing
+
+// Decides which server owns each key and location
+type Partitioner interface {
+	// Hash of key in the upper 32 bits of its locations
+	KeyHash(key string) uint32
+	// Server owning key
+	LocateKey(key string) string
+	// Server owning the node of key at location
+	Owner(key string, loc uint64) string
+	// Servers which might own the node at location,
+	// only one unless nodes are placed by more of their key than the key hash
+	Owners(loc uint64) []string
+	// Ranges of key hashes of nodes owned by server
+	Ranges(server string) []Mapping
+	// Range of key hashes of keys in [startKey, endKey) (empty endKey for no end)
+	ScanRange(startKey, endKey string) (uint32, uint32)
+	// Propose moving part of the keys of server to target.
+	// samples are the nodes stored on server.
+	ProposeSplit(server, target string, samples []Sample) (Split, bool)
+	ApplySplit(split Split)
+	// Copy to apply splits without changing the routing in use
+	Clone() Partitioner
+	Print()
+}
+
+// Key and key hash of a node considered by a split
+type Sample struct {
+	Key     string
+	KeyHash uint32
+}
+
+// Change of ownership broadcast to all servers
+type Split struct {
+	// [Left, Mid] to LeftServer and [Mid + 1, Right] to RightServer
+	Left        uint32
+	Right       uint32
+	Mid         uint32
+	LeftServer  string
+	RightServer string
+	// Server joining or leaving the hash ring
+	Join  string
+	Leave string
+	// Keys of the split key range from Key go to RightServer, the others to LeftServer
+	Key string
+}
+
+// Whether any range shares a key hash with another list of ranges
+func Overlap(ranges, others []Mapping) bool {
+	for _, r := range ranges {
+		for _, m := range others {
+			if r.Left <= m.Right && m.Left <= r.Right {
+				return true
+			}
+		}
+	}
+	return false
+}
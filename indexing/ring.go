@@ -0,0 +1,170 @@
+package indexing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/DCsunset/openwhisk-grpc/utils"
+)
+
+const DefaultVirtualNodes = 64
+
+// Position of a virtual node on the ring
+type Token struct {
+	Hash    uint32
+	Address string
+}
+
+// Consistent hashing ring over key hashes.
+// Each server has several virtual nodes so that a joining or leaving server
+// moves a small part of data from or to every other server.
+type Ring struct {
+	VirtualNodes int
+	Tokens       []Token // sorted by hash
+}
+
+func NewRing(virtualNodes int) *Ring {
+	if virtualNodes <= 0 {
+		virtualNodes = DefaultVirtualNodes
+	}
+	return &Ring{VirtualNodes: virtualNodes}
+}
+
+func (r *Ring) Clone() Partitioner {
+	return &Ring{
+		VirtualNodes: r.VirtualNodes,
+		Tokens:       append([]Token(nil), r.Tokens...),
+	}
+}
+
+func (r *Ring) Has(server string) bool {
+	for _, token := range r.Tokens {
+		if token.Address == server {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Ring) Add(server string) {
+	if r.Has(server) {
+		return
+	}
+	for i := 0; i < r.VirtualNodes; i += 1 {
+		hash := utils.Hash2Uint(utils.Hash([]byte(fmt.Sprintf("%s#%d", server, i))))
+		r.Tokens = append(r.Tokens, Token{hash, server})
+	}
+	// Break ties by address so that all servers agree
+	sort.Slice(r.Tokens, func(i, j int) bool {
+		a, b := r.Tokens[i], r.Tokens[j]
+		return a.Hash < b.Hash || (a.Hash == b.Hash && a.Address < b.Address)
+	})
+}
+
+func (r *Ring) Remove(server string) {
+	tokens := r.Tokens[:0]
+	for _, token := range r.Tokens {
+		if token.Address != server {
+			tokens = append(tokens, token)
+		}
+	}
+	r.Tokens = tokens
+}
+
+// The first virtual node clockwise from keyHash owns it
+func (r *Ring) LocateHash(keyHash uint32) string {
+	if len(r.Tokens) == 0 {
+		panic(fmt.Sprintf("Key hash %x not found", keyHash))
+	}
+	i := sort.Search(len(r.Tokens), func(i int) bool {
+		return r.Tokens[i].Hash >= keyHash
+	})
+	if i == len(r.Tokens) {
+		i = 0
+	}
+	return r.Tokens[i].Address
+}
+
+func (r *Ring) Owner(key string, loc uint64) string {
+	return r.LocateHash(utils.KeyHash(loc))
+}
+
+func (r *Ring) Owners(loc uint64) []string {
+	return []string{r.LocateHash(utils.KeyHash(loc))}
+}
+
+func (r *Ring) KeyHash(key string) uint32 {
+	return KeyHash(key)
+}
+
+func (r *Ring) LocateKey(key string) string {
+	return r.LocateHash(KeyHash(key))
+}
+
+// Sorted arcs of key hashes owned by server
+func (r *Ring) Ranges(server string) []Mapping {
+	var arcs []Mapping
+	for i, token := range r.Tokens {
+		if token.Address != server {
+			continue
+		}
+		if i == 0 {
+			arcs = append(arcs, Mapping{0, token.Hash, server})
+			// Wrap around from the last token
+			if last := r.Tokens[len(r.Tokens)-1].Hash; last < math.MaxUint32 {
+				arcs = append(arcs, Mapping{last + 1, math.MaxUint32, server})
+			}
+		} else if prev := r.Tokens[i-1].Hash; prev < token.Hash {
+			arcs = append(arcs, Mapping{prev + 1, token.Hash, server})
+		}
+	}
+	sort.Slice(arcs, func(i, j int) bool {
+		return arcs[i].Left < arcs[j].Left
+	})
+
+	// Merge adjacent arcs
+	var ranges []Mapping
+	for _, arc := range arcs {
+		if l := len(ranges); l > 0 && ranges[l-1].Right+1 == arc.Left {
+			ranges[l-1].Right = arc.Right
+		} else {
+			ranges = append(ranges, arc)
+		}
+	}
+	return ranges
+}
+
+// Keys are spread over all hashes
+func (r *Ring) ScanRange(startKey, endKey string) (uint32, uint32) {
+	return 0, math.MaxUint32
+}
+
+// The target joins the ring and takes keys from every server
+func (r *Ring) ProposeSplit(server, target string, samples []Sample) (Split, bool) {
+	if r.Has(target) {
+		return Split{}, false
+	}
+	return Split{Join: target}, true
+}
+
+func (r *Ring) ApplySplit(split Split) {
+	if len(split.Join) > 0 {
+		r.Add(split.Join)
+	}
+	if len(split.Leave) > 0 {
+		r.Remove(split.Leave)
+	}
+}
+
+func (r *Ring) Print() {
+	fmt.Printf("Ring (%d virtual nodes per server):\n", r.VirtualNodes)
+	count := make(map[string]uint64)
+	for i, token := range r.Tokens {
+		prev := r.Tokens[(i+len(r.Tokens)-1)%len(r.Tokens)].Hash
+		count[token.Address] += uint64(token.Hash - prev)
+	}
+	for server, size := range count {
+		fmt.Printf("%s: %.1f%%\n", server, float64(size)*100/math.MaxUint32)
+	}
+}
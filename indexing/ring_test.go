@@ -0,0 +1,23 @@
+package indexing
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRingLeaveMovesKeysToOthers(t *testing.T) {
+	r := NewRing(8)
+	r.Add("a")
+	r.ApplySplit(Split{Join: "b"})
+	if len(r.Ranges("b")) == 0 {
+		t.Fatalf("Joining server owns no keys")
+	}
+
+	r.ApplySplit(Split{Leave: "b"})
+	if r.Has("b") {
+		t.Fatalf("Server is still in the ring after leaving")
+	}
+	if ranges := r.Ranges("a"); len(ranges) != 1 || ranges[0].Left != 0 || ranges[0].Right != math.MaxUint32 {
+		t.Fatalf("Remaining server owns %v instead of all keys", ranges)
+	}
+}
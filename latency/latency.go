@@ -0,0 +1,247 @@
+// Simulated latency of storage operations,
+// shared by the db server and the simple-db baseline
+package latency
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Distribution types
+const (
+	None    = "none"
+	Fixed   = "fixed"
+	Uniform = "uniform"
+	Normal  = "normal"
+	Trace   = "trace"
+)
+
+// Latency distribution of an operation (parameters in ms)
+type Distribution struct {
+	// none, fixed, uniform, normal or trace
+	Type string `json:"type"`
+	// Latency of fixed distribution
+	Value float64 `json:"value"`
+	// Range of uniform distribution
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	// Parameters of normal distribution (negative samples are 0)
+	Mean   float64 `json:"mean"`
+	Stddev float64 `json:"stddev"`
+	// File of recorded latencies in ms, one per line, replayed in order
+	Trace string `json:"trace"`
+}
+
+type Config struct {
+	Get    Distribution `json:"get"`
+	Set    Distribution `json:"set"`
+	Delete Distribution `json:"delete"`
+}
+
+// Read the latency field of a JSON configuration file
+func Load(path string) (Config, error) {
+	var file struct {
+		Latency Config `json:"latency"`
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return file.Latency, err
+	}
+	err = json.Unmarshal(data, &file)
+	return file.Latency, err
+}
+
+// Add flags to override the distribution of each operation
+func (c *Config) RegisterFlags(flags *flag.FlagSet) {
+	usage := "latency of %s: none, fixed:ms, uniform:min:max, normal:mean:stddev or trace:file"
+	flags.Var(&c.Get, "latency.get", fmt.Sprintf(usage, "Get"))
+	flags.Var(&c.Set, "latency.set", fmt.Sprintf(usage, "Set"))
+	flags.Var(&c.Delete, "latency.delete", fmt.Sprintf(usage, "Delete"))
+}
+
+func (d *Distribution) String() string {
+	switch d.Type {
+	case Fixed:
+		return fmt.Sprintf("%s:%g", d.Type, d.Value)
+	case Uniform:
+		return fmt.Sprintf("%s:%g:%g", d.Type, d.Min, d.Max)
+	case Normal:
+		return fmt.Sprintf("%s:%g:%g", d.Type, d.Mean, d.Stddev)
+	case Trace:
+		return fmt.Sprintf("%s:%s", d.Type, d.Trace)
+	}
+	return None
+}
+
+// Parse a distribution like uniform:5:15
+func (d *Distribution) Set(spec string) error {
+	parts := strings.SplitN(spec, ":", 2)
+	typ := parts[0]
+	var args []string
+	if len(parts) == 2 {
+		if typ == Trace {
+			// Path might contain colons
+			args = parts[1:]
+		} else {
+			args = strings.Split(parts[1], ":")
+		}
+	}
+
+	expected := map[string]int{None: 0, Fixed: 1, Uniform: 2, Normal: 2, Trace: 1}
+	n, ok := expected[typ]
+	if !ok {
+		return fmt.Errorf("Unknown latency distribution %s", typ)
+	}
+	if len(args) != n {
+		return fmt.Errorf("Latency distribution %s takes %d parameters", typ, n)
+	}
+	var params []float64
+	if typ != Trace {
+		for _, arg := range args {
+			param, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return err
+			}
+			params = append(params, param)
+		}
+	}
+
+	*d = Distribution{Type: typ}
+	switch typ {
+	case Fixed:
+		d.Value = params[0]
+	case Uniform:
+		d.Min, d.Max = params[0], params[1]
+	case Normal:
+		d.Mean, d.Stddev = params[0], params[1]
+	case Trace:
+		d.Trace = args[0]
+	}
+	return nil
+}
+
+type sampler func() time.Duration
+
+func millisecond(ms float64) time.Duration {
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+func (d *Distribution) sampler() (sampler, error) {
+	switch d.Type {
+	case "", None:
+		return nil, nil
+	case Fixed:
+		latency := millisecond(d.Value)
+		return func() time.Duration {
+			return latency
+		}, nil
+	case Uniform:
+		if d.Max < d.Min {
+			return nil, fmt.Errorf("Invalid uniform latency [%g, %g]", d.Min, d.Max)
+		}
+		return func() time.Duration {
+			return millisecond(d.Min + rand.Float64()*(d.Max-d.Min))
+		}, nil
+	case Normal:
+		return func() time.Duration {
+			return millisecond(d.Mean + rand.NormFloat64()*d.Stddev)
+		}, nil
+	case Trace:
+		samples, err := readTrace(d.Trace)
+		if err != nil {
+			return nil, err
+		}
+		var next uint64
+		return func() time.Duration {
+			i := atomic.AddUint64(&next, 1) - 1
+			return samples[i%uint64(len(samples))]
+		}, nil
+	}
+	return nil, fmt.Errorf("Unknown latency distribution %s", d.Type)
+}
+
+func readTrace(path string) ([]time.Duration, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var samples []time.Duration
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		ms, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid latency %q in trace %s", line, path)
+		}
+		samples = append(samples, millisecond(ms))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("Empty latency trace %s", path)
+	}
+	return samples, nil
+}
+
+// Injector delays operations according to a configuration.
+// A nil Injector adds no latency.
+type Injector struct {
+	get, set, delete sampler
+}
+
+func New(config Config) (*Injector, error) {
+	var i Injector
+	var err error
+	if i.get, err = config.Get.sampler(); err != nil {
+		return nil, err
+	}
+	if i.set, err = config.Set.sampler(); err != nil {
+		return nil, err
+	}
+	if i.delete, err = config.Delete.sampler(); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+func wait(s sampler) {
+	if s != nil {
+		time.Sleep(s())
+	}
+}
+
+func (i *Injector) Get() {
+	if i != nil {
+		wait(i.get)
+	}
+}
+
+func (i *Injector) Set() {
+	if i != nil {
+		wait(i.set)
+	}
+}
+
+func (i *Injector) Delete() {
+	if i != nil {
+		wait(i.delete)
+	}
+}
@@ -3,8 +3,12 @@ package main
 import (
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
 	"google.golang.org/grpc"
 )
 
@@ -18,10 +22,25 @@ func main() {
 
 	server := Server{}
 	server.Init()
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(server.routeUnary),
+		grpc.StreamInterceptor(server.routeStream),
+	)
 	db.RegisterDbServiceServer(grpcServer, &server)
+	dbv2.RegisterDbServiceServer(grpcServer, &ServerV2{server: &server})
+
+	// Flush the store before exiting
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		grpcServer.GracefulStop()
+	}()
 
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
 	}
+	if err := store.Close(); err != nil {
+		log.Fatalln(err)
+	}
 }
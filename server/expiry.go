@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+)
+
+func (s *Server) expiryLoop() {
+	ticker := time.NewTicker(time.Second * time.Duration(s.ExpiryInterval))
+	defer ticker.Stop()
+	for range ticker.C {
+		s.expire()
+	}
+}
+
+// Remove expired heads.
+// Other expired nodes are kept for reads as of a time before they expired,
+// while reads after it stop at them, which hides their older versions.
+func (s *Server) expire() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	table := indexingService.Table()
+	ctx := withTable(context.Background(), table)
+	now := time.Now()
+
+	var expired []*storage.Node
+	for _, r := range table.Ranges(s.Self) {
+		store.Range(r.Left, r.Right, func(node *storage.Node) bool {
+			if node.Expired(now) && len(node.Children) == 0 {
+				n := *node
+				expired = append(expired, &n)
+			}
+			return true
+		})
+	}
+
+	for _, node := range expired {
+		_, err := s.Relink(ctx, &db.RelinkRequest{
+			Location: node.Dep,
+			Removed:  node.Location,
+			Dep:      node.Dep,
+		})
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		storage.Remove(store, node.Location)
+	}
+
+	// Debug
+	fmt.Println("[Expire]")
+	fmt.Printf("Expired: %d\n", len(expired))
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpireKeepsAncestorsForAsOf(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	expires := time.Now().Add(100 * time.Millisecond)
+	parent := storage.Set(store, storage.Node{Key: "a", Value: []byte("1"), Expires: expires.UnixNano()}, 0)
+	child := storage.Set(store, storage.Node{Key: "b", Value: []byte("2"), Dep: parent}, 0)
+	store.AddChild(parent, child)
+	asOf := time.Now().UnixNano()
+
+	time.Sleep(time.Until(expires))
+	s.expire()
+
+	resp, err := s.Get(ctx, &db.GetRequest{Key: "a", Location: child, AsOf: asOf})
+	if err != nil || resp.Value != "1" {
+		t.Fatalf("Get before expiry returns %v, %v after sweeping", resp, err)
+	}
+	if _, err := s.Get(ctx, &db.GetRequest{Key: "a", Location: child}); status.Code(err) != codes.NotFound {
+		t.Fatalf("Expired version is read with error %v", err)
+	}
+}
+
+func TestExpireRemovesExpiredHeads(t *testing.T) {
+	s := newTestServer(t)
+
+	parent := storage.Set(store, storage.Node{Key: "a", Value: []byte("1")}, 0)
+	head := storage.Set(store, storage.Node{Key: "a", Value: []byte("2"), Dep: parent, Expires: time.Now().UnixNano()}, 0)
+	store.AddChild(parent, head)
+	s.expire()
+
+	if store.GetNode(head) != nil {
+		t.Fatalf("Expired head is not removed")
+	}
+	if node := store.GetNode(parent); node == nil || len(node.Children) != 0 {
+		t.Fatalf("Parent of the expired head is %v", node)
+	}
+}
+
+// Nodes moved to other servers expire at the same time
+func TestAddNodeKeepsExpires(t *testing.T) {
+	s := newTestServer(t)
+	v2 := &ServerV2{server: s}
+	ctx := context.Background()
+
+	expires := time.Now().Add(time.Hour).UnixNano()
+	loc := storage.Set(store, storage.Node{Key: "a", Value: []byte("1"), Expires: expires}, 0)
+	node := store.GetNode(loc)
+
+	storage.Remove(store, loc)
+	if _, err := s.AddNode(ctx, &db.AddNodeRequest{Node: node.Proto()}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.GetNode(loc).Expires; got != expires {
+		t.Fatalf("Node added by v1 expires at %d instead of %d", got, expires)
+	}
+
+	storage.Remove(store, loc)
+	if _, err := v2.AddNode(ctx, &dbv2.AddNodeRequest{Node: node.ProtoV2()}); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.GetNode(loc).Expires; got != expires {
+		t.Fatalf("Node added by v2 expires at %d instead of %d", got, expires)
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+)
+
+func (s *Server) gcLoop() {
+	ticker := time.NewTicker(time.Second * time.Duration(s.GC.Interval))
+	defer ticker.Stop()
+	for range ticker.C {
+		s.collect()
+	}
+}
+
+// Remove superseded versions owned by this server
+func (s *Server) collect() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	table := indexingService.Table()
+	ctx := withTable(context.Background(), table)
+	functions := s.mergeFunctions()
+	pinned := func(loc uint64) bool {
+		_, ok := functions[loc]
+		return ok
+	}
+	lookup := func(loc uint64) (*storage.Node, error) {
+		return s.getNode(ctx, loc)
+	}
+
+	// Versions of a key are always in the same range
+	var collected []*storage.Node
+	for _, r := range table.Ranges(s.Self) {
+		collected = append(collected, storage.Collect(store, r.Left, r.Right, s.GC, pinned, lookup)...)
+	}
+
+	removed := 0
+	for _, n := range collected {
+		// Ancestors might have been collapsed into it
+		node := store.GetNode(n.Location)
+		if node == nil {
+			continue
+		}
+		dep := node.Dep
+		children := append([]uint64(nil), node.Children...)
+
+		// Link its parent and children directly
+		request := &db.RelinkRequest{
+			Location: dep,
+			Removed:  node.Location,
+			Dep:      dep,
+			Children: children,
+		}
+		if _, err := s.Relink(ctx, request); err != nil {
+			log.Println(err)
+			continue
+		}
+		for _, child := range children {
+			request.Location = child
+			if _, err := s.Relink(ctx, request); err != nil {
+				log.Println(err)
+			}
+		}
+		storage.Remove(store, n.Location)
+		removed += 1
+	}
+
+	// Debug
+	fmt.Println("[GC]")
+	fmt.Printf("Removed: %d\n", removed)
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+	"github.com/DCsunset/openwhisk-grpc/indexing"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+	"google.golang.org/grpc"
+)
+
+// The table to switch to after splits, including those not rebalanced yet
+func (s *Server) latestTable() *indexing.Table {
+	if s.nextTable != nil {
+		return s.nextTable
+	}
+	return indexingService.Table()
+}
+
+// Use a newer routing table and return whether local nodes have to move first
+func (s *Server) adoptTable(next *indexing.Table) bool {
+	s.partitionLock.Lock()
+	defer s.partitionLock.Unlock()
+
+	if next.Epoch <= s.latestTable().Epoch {
+		return false
+	}
+
+	// Remove servers in use from available servers
+	available := s.AvailableServers[:0]
+	for _, addr := range s.AvailableServers {
+		if len(next.Ranges(addr)) == 0 {
+			available = append(available, addr)
+		}
+	}
+	// Servers leaving the ring can be used again
+	current := s.latestTable()
+	for _, addr := range s.Servers {
+		if len(current.Ranges(addr)) > 0 && len(next.Ranges(addr)) == 0 {
+			available = append(available, addr)
+		}
+	}
+	s.AvailableServers = available
+
+	if s.nextTable == nil && !s.losesNodes(next) {
+		// No local keys move
+		indexingService.SetTable(next)
+		return false
+	}
+	s.nextTable = next
+	return true
+}
+
+// Whether other servers own key hashes of local nodes in next.
+// Nodes of key hashes shared by several servers are assumed to move.
+func (s *Server) losesNodes(next *indexing.Table) bool {
+	ranges := indexingService.Table().Ranges(s.Self)
+	for _, addr := range s.Servers {
+		if addr != s.Self && indexing.Overlap(ranges, next.Ranges(addr)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Move nodes owned by other servers in the next table and then switch to it
+func (s *Server) rebalance() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.switchNextTable()
+}
+
+// Same as rebalance with the lock held
+func (s *Server) switchNextTable() {
+	s.partitionLock.Lock()
+	next := s.nextTable
+	s.partitionLock.Unlock()
+	if next == nil {
+		// Done by an earlier rebalance
+		return
+	}
+	s.switchTable(next, s.transfer(next))
+}
+
+// Copy nodes to their owners in next so that they are readable once routed there
+func (s *Server) transfer(next *indexing.Table) []*storage.Node {
+	var moved []*storage.Node
+	store.Range(0, math.MaxUint32, func(node *storage.Node) bool {
+		if next.Owner(node.Key, node.Location) != s.Self {
+			n := *node
+			moved = append(moved, &n)
+		}
+		return true
+	})
+
+	// One connection to each owner for all of its nodes
+	conns := make(map[string]*grpc.ClientConn)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	ctx := context.Background()
+	for _, node := range moved {
+		err := func() error {
+			address := next.Owner(node.Key, node.Location)
+			conn, ok := conns[address]
+			if !ok {
+				var err error
+				conn, err = grpc.Dial(address, dialOptions...)
+				if err != nil {
+					return err
+				}
+				conns[address] = conn
+			}
+			_, err := dbv2.NewDbServiceClient(conn).AddNode(ctx, &dbv2.AddNodeRequest{
+				Node: node.ProtoV2(),
+			})
+			if err != nil {
+				return err
+			}
+
+			// Transfer merge function
+			s.mergeLock.RLock()
+			f, ok := s.mergeFunction[node.Location]
+			s.mergeLock.RUnlock()
+			if ok {
+				_, err = db.NewDbServiceClient(conn).SetMergeFunction(ctx, &db.SetMergeFunctionRequest{
+					Location: node.Location,
+					Name:     f,
+				})
+				if err != nil {
+					return err
+				}
+				s.SetMergeFunction(ctx, &db.SetMergeFunctionRequest{Location: node.Location})
+			}
+			return nil
+		}()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	// Debug
+	fmt.Println("[Transfer]")
+	fmt.Printf("AddNodes: %d\n", len(moved))
+	return moved
+}
+
+// Route with next and remove the nodes moved to other servers
+func (s *Server) switchTable(next *indexing.Table, moved []*storage.Node) {
+	s.partitionLock.Lock()
+	indexingService.SetTable(next)
+	if s.nextTable == next {
+		s.nextTable = nil
+	}
+	s.partitionLock.Unlock()
+
+	for _, node := range moved {
+		store.RemoveNode(node.Location)
+	}
+
+	// Debug
+	fmt.Println("[Rebalance]")
+	next.Print()
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/indexing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Metadata of requests between servers with the epoch of the routing table used
+const epochKey = "routing-epoch"
+
+// Metadata of requests sent to one of the servers which might own a location in turn,
+// with the location in hex
+const probeKey = "routing-probe"
+
+// Retries of a request after the routing table is refreshed
+const maxRouteRetries = 3
+
+// Requests routed by the owner of a key or location
+var routedMethods = map[string]bool{
+	"/db.DbService/Get":            true,
+	"/db.DbService/Set":            true,
+	"/db.DbService/Delete":         true,
+	"/db.DbService/GetNode":        true,
+	"/db.DbService/AddChild":       true,
+	"/db.DbService/RemoveChildren": true,
+	"/db.DbService/Relink":         true,
+	"/db.v2.DbService/Get":         true,
+	"/db.v2.DbService/Set":         true,
+	"/db.v2.DbService/Delete":      true,
+	"/db.v2.DbService/PutStream":   true,
+	"/db.v2.DbService/GetStream":   true,
+	"/db.v2.DbService/GetNode":     true,
+	"/db.v2.DbService/Scan":        true,
+}
+
+// Time to wait for a newer routing table to be in use before retrying
+const refreshTimeout = 5 * time.Second
+
+type tableKey struct{}
+
+// Route the request and the requests forwarded by it with table
+func withTable(ctx context.Context, table *indexing.Table) context.Context {
+	return context.WithValue(ctx, tableKey{}, table)
+}
+
+// Routing table of a request, which stays the same while it is handled
+func tableOf(ctx context.Context) *indexing.Table {
+	if table, ok := ctx.Value(tableKey{}).(*indexing.Table); ok {
+		return table
+	}
+	return indexingService.Table()
+}
+
+func encodeTable(table *indexing.Table) (*db.RoutingTable, error) {
+	data, err := json.Marshal(table.Partitioner)
+	if err != nil {
+		return nil, err
+	}
+	return &db.RoutingTable{Epoch: table.Epoch, Partitioner: data}, nil
+}
+
+// All servers use the same type of partitioner
+func decodeTable(table *db.RoutingTable) (*indexing.Table, error) {
+	if table == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Routing table is missing")
+	}
+	p := indexingService.Table().Clone()
+	if err := json.Unmarshal(table.Partitioner, p); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid routing table: %v", err)
+	}
+	return &indexing.Table{Partitioner: p, Epoch: table.Epoch}, nil
+}
+
+// Use the routing table in a wrong owner error and return whether the request
+// can be retried, which waits till the table is in use if nodes have to move first
+func (s *Server) refreshTable(ctx context.Context, err error) bool {
+	if status.Code(err) != codes.FailedPrecondition {
+		return false
+	}
+	for _, detail := range status.Convert(err).Details() {
+		table, ok := detail.(*db.RoutingTable)
+		if !ok {
+			continue
+		}
+		next, err := decodeTable(table)
+		if err != nil {
+			return false
+		}
+		before := tableOf(ctx).Epoch
+		if next.Epoch <= before {
+			return false
+		}
+		if s.adoptTable(next) {
+			go s.rebalance()
+		}
+
+		// Debug
+		fmt.Println("[RefreshTable]")
+		fmt.Printf("Epoch: %d -> %d\n", before, next.Epoch)
+
+		ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+		defer cancel()
+		return indexingService.Wait(ctx, next.Epoch) == nil
+	}
+	return false
+}
+
+// Reject requests routed by an older routing table
+func (s *Server) checkEpoch(ctx context.Context, method string) error {
+	if !routedMethods[method] {
+		return nil
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(epochKey)) == 0 {
+		// Not forwarded by another server
+		return nil
+	}
+	values := md.Get(epochKey)
+	epoch, err := strconv.ParseUint(values[len(values)-1], 10, 64)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid routing epoch %s", values[len(values)-1])
+	}
+	current := indexingService.Table()
+	if epoch >= current.Epoch {
+		return nil
+	}
+
+	table, err := encodeTable(current)
+	if err != nil {
+		return err
+	}
+	st, err := status.New(codes.FailedPrecondition, fmt.Sprintf(
+		"Wrong owner: routing epoch %d is older than %d", epoch, current.Epoch,
+	)).WithDetails(table)
+	if err != nil {
+		return err
+	}
+	return st.Err()
+}
+
+// Retry requests failed by stale routing after refreshing the routing table
+func (s *Server) routeUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.checkEpoch(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	for retries := 0; ; retries += 1 {
+		ctx := withTable(ctx, indexingService.Table())
+		resp, err := handler(ctx, req)
+		if retries < maxRouteRetries && s.refreshTable(ctx, err) {
+			continue
+		}
+		return resp, err
+	}
+}
+
+// Replays the request of a server-streaming call
+type replayStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	request proto.Message
+	replay  bool
+	sent    bool
+}
+
+func (s *replayStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *replayStream) RecvMsg(m interface{}) error {
+	if s.replay {
+		s.replay = false
+		proto.Merge(m.(proto.Message), s.request)
+		return nil
+	}
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.request == nil {
+		s.request = proto.Clone(m.(proto.Message))
+	}
+	return err
+}
+
+func (s *replayStream) SendMsg(m interface{}) error {
+	s.sent = true
+	return s.ServerStream.SendMsg(m)
+}
+
+// Retry streams only if nothing has been sent and the request can be replayed
+func (s *Server) routeStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkEpoch(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	stream := &replayStream{ServerStream: ss}
+	for retries := 0; ; retries += 1 {
+		stream.ctx = withTable(ss.Context(), indexingService.Table())
+		err := handler(srv, stream)
+		if retries < maxRouteRetries && !info.IsClientStream && !stream.sent && s.refreshTable(stream.ctx, err) {
+			stream.replay = stream.request != nil
+			continue
+		}
+		return err
+	}
+}
+
+func withEpoch(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, epochKey, strconv.FormatUint(tableOf(ctx).Epoch, 10))
+}
+
+// Send the epoch with requests to other servers
+func forwardUnary(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(withEpoch(ctx), method, req, reply, cc, opts...)
+}
+
+func forwardStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(withEpoch(ctx), desc, cc, method, opts...)
+}
+
+// Whether the request is sent by a server trying each owner of loc in turn,
+// so that a missing node is reported instead of forwarded again
+func probed(ctx context.Context, loc uint64) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	for _, value := range md.Get(probeKey) {
+		if value == strconv.FormatUint(loc, 16) {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether the node at loc is handled by this server.
+// With several possible owners it is handled by the one storing it.
+func (s *Server) ownsLocation(ctx context.Context, loc uint64) bool {
+	if probed(ctx, loc) {
+		return true
+	}
+	owners := tableOf(ctx).Owners(loc)
+	for _, owner := range owners {
+		if owner == s.Self {
+			return len(owners) == 1 || store.GetNode(loc) != nil
+		}
+	}
+	return false
+}
+
+// Forward a request about the node at loc to the other servers which might own it
+// till one of them does not return NotFound
+func (s *Server) forwardLocation(ctx context.Context, loc uint64, forward func(ctx context.Context, conn *grpc.ClientConn) error) error {
+	owners := tableOf(ctx).Owners(loc)
+	if len(owners) > 1 {
+		ctx = metadata.AppendToOutgoingContext(ctx, probeKey, strconv.FormatUint(loc, 16))
+	}
+	err := status.Errorf(codes.NotFound, "Location %x not found", loc)
+	for _, owner := range owners {
+		if owner == s.Self {
+			continue
+		}
+		conn, dialErr := grpc.Dial(owner, dialOptions...)
+		if dialErr != nil {
+			return dialErr
+		}
+		err = forward(ctx, conn)
+		conn.Close()
+		if status.Code(err) != codes.NotFound {
+			return err
+		}
+	}
+	return err
+}
+
+// Whether the version read by a get request is on this server
+func (s *Server) localGet(ctx context.Context, key string, loc uint64) bool {
+	if len(key) == 0 {
+		return s.ownsLocation(ctx, loc)
+	}
+	return tableOf(ctx).LocateKey(key) == s.Self
+}
+
+// Forward a get request to the server with the version read by it
+func (s *Server) forwardGet(ctx context.Context, key string, loc uint64, forward func(ctx context.Context, conn *grpc.ClientConn) error) error {
+	if len(key) == 0 {
+		return s.forwardLocation(ctx, loc, forward)
+	}
+	conn, err := grpc.Dial(tableOf(ctx).LocateKey(key), dialOptions...)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	return forward(ctx, conn)
+}
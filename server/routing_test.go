@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/indexing"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Routing table after the current one without moving any keys
+func nextTable() *indexing.Table {
+	current := indexingService.Table()
+	return &indexing.Table{Partitioner: current.Clone(), Epoch: current.Epoch + 1}
+}
+
+// Error of a server routing with table
+func staleError(t *testing.T, table *indexing.Table) error {
+	encoded, err := encodeTable(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := status.New(codes.FailedPrecondition, "Wrong owner").WithDetails(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st.Err()
+}
+
+func withIncomingEpoch(epoch string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(epochKey, epoch))
+}
+
+func TestCheckEpochRejectsOlderEpoch(t *testing.T) {
+	s := newTestServer(t)
+	indexingService.SetTable(nextTable())
+
+	err := s.checkEpoch(withIncomingEpoch("0"), "/db.DbService/Get")
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("Older epoch is rejected with error %v", err)
+	}
+	details := status.Convert(err).Details()
+	if len(details) != 1 {
+		t.Fatalf("Error details are %v instead of the routing table", details)
+	}
+	table, err := decodeTable(details[0].(*db.RoutingTable))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Epoch != 1 || table.LocateKey("key") != s.Self {
+		t.Fatalf("Routing table in error has epoch %d and routes key to %s", table.Epoch, table.LocateKey("key"))
+	}
+
+	for _, c := range []struct {
+		ctx    context.Context
+		method string
+	}{
+		{withIncomingEpoch("1"), "/db.DbService/Get"},
+		{withIncomingEpoch("2"), "/db.DbService/Get"},
+		// Not forwarded by another server
+		{context.Background(), "/db.DbService/Get"},
+		// Not routed by the owner
+		{withIncomingEpoch("0"), "/db.DbService/Split"},
+	} {
+		if err := s.checkEpoch(c.ctx, c.method); err != nil {
+			t.Fatalf("%s is rejected with error %v", c.method, err)
+		}
+	}
+	if err := s.checkEpoch(withIncomingEpoch("x"), "/db.DbService/Get"); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("Invalid epoch is rejected with error %v", err)
+	}
+}
+
+func TestRefreshTable(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	next := nextTable()
+
+	if s.refreshTable(ctx, status.Errorf(codes.NotFound, "Not found")) {
+		t.Fatalf("Request failed by another error is retried")
+	}
+	if !s.refreshTable(ctx, staleError(t, next)) {
+		t.Fatalf("Request failed by an older epoch is not retried")
+	}
+	if epoch := indexingService.Table().Epoch; epoch != next.Epoch {
+		t.Fatalf("Routing table in use has epoch %d instead of %d", epoch, next.Epoch)
+	}
+	if s.refreshTable(withTable(ctx, next), staleError(t, next)) {
+		t.Fatalf("Request failed by the same epoch is retried")
+	}
+}
+
+func TestRouteUnaryRetriesWithNewerTable(t *testing.T) {
+	s := newTestServer(t)
+	next := nextTable()
+
+	var epochs []uint64
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		epochs = append(epochs, tableOf(ctx).Epoch)
+		if len(epochs) == 1 {
+			return nil, staleError(t, next)
+		}
+		return &db.Empty{}, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/db.DbService/Get"}
+	if _, err := s.routeUnary(context.Background(), &db.GetRequest{}, info, handler); err != nil {
+		t.Fatal(err)
+	}
+	if len(epochs) != 2 || epochs[1] != next.Epoch {
+		t.Fatalf("Request is handled with epochs %v", epochs)
+	}
+}
+
+// Server stream receiving requests once
+type testStream struct {
+	grpc.ServerStream
+	requests []proto.Message
+	sent     int
+}
+
+func (s *testStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *testStream) RecvMsg(m interface{}) error {
+	if len(s.requests) == 0 {
+		return io.EOF
+	}
+	proto.Merge(m.(proto.Message), s.requests[0])
+	s.requests = s.requests[1:]
+	return nil
+}
+
+func (s *testStream) SendMsg(m interface{}) error {
+	s.sent += 1
+	return nil
+}
+
+func TestRouteStreamReplaysRequest(t *testing.T) {
+	s := newTestServer(t)
+	info := &grpc.StreamServerInfo{FullMethod: "/db.v2.DbService/GetStream", IsServerStream: true}
+
+	for _, sendFirst := range []bool{false, true} {
+		next := nextTable()
+		var keys []string
+		handler := func(srv interface{}, stream grpc.ServerStream) error {
+			in := &db.GetRequest{}
+			if err := stream.RecvMsg(in); err != nil {
+				return err
+			}
+			keys = append(keys, in.Key)
+			if len(keys) == 1 {
+				if sendFirst {
+					stream.SendMsg(&db.GetResponse{})
+				}
+				return staleError(t, next)
+			}
+			return stream.SendMsg(&db.GetResponse{})
+		}
+
+		ss := &testStream{requests: []proto.Message{&db.GetRequest{Key: "key"}}}
+		err := s.routeStream(nil, ss, info, handler)
+		if sendFirst {
+			// Responses already sent cannot be taken back
+			if status.Code(err) != codes.FailedPrecondition || len(keys) != 1 {
+				t.Fatalf("Stream sending before failing is handled %d times with error %v", len(keys), err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 2 || keys[1] != "key" || ss.sent != 1 {
+			t.Fatalf("Stream is handled with requests %q and sends %d responses", keys, ss.sent)
+		}
+	}
+}
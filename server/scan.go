@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (self *ServerV2) Scan(in *dbv2.ScanRequest, stream dbv2.DbService_ScanServer) error {
+	if in.Local {
+		results, err := self.server.scan(stream.Context(), in)
+		if err != nil {
+			return err
+		}
+		for _, resp := range results {
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Stop other servers once enough keys are sent
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// Sorted results from every server owning part of the range
+	var sources []func() (*dbv2.GetResponse, error)
+	local := &dbv2.ScanRequest{
+		StartKey: in.StartKey,
+		EndKey:   in.EndKey,
+		Location: in.Location,
+		Limit:    in.Limit,
+		Local:    true,
+	}
+	table := tableOf(ctx)
+	left, right := table.ScanRange(string(in.StartKey), string(in.EndKey))
+	for _, addr := range self.server.Servers {
+		owner := false
+		for _, r := range table.Ranges(addr) {
+			if r.Left <= right && r.Right >= left {
+				owner = true
+			}
+		}
+		if !owner {
+			continue
+		}
+
+		if addr == self.server.Self {
+			results, err := self.server.scan(ctx, local)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, func() (*dbv2.GetResponse, error) {
+				if len(results) == 0 {
+					return nil, io.EOF
+				}
+				resp := results[0]
+				results = results[1:]
+				return resp, nil
+			})
+		} else {
+			conn, err := grpc.Dial(addr, dialOptions...)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+			client, err := dbv2.NewDbServiceClient(conn).Scan(ctx, local)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, client.Recv)
+		}
+	}
+
+	return mergeScan(sources, in.Limit, stream.Send)
+}
+
+// Send the results of sources sorted by key merged by key, till limit (0 for no limit)
+func mergeScan(sources []func() (*dbv2.GetResponse, error), limit uint32, send func(*dbv2.GetResponse) error) error {
+	heads := make([]*dbv2.GetResponse, len(sources))
+	for i, source := range sources {
+		resp, err := source()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		heads[i] = resp
+	}
+	for sent := uint32(0); limit == 0 || sent < limit; sent += 1 {
+		min := -1
+		for i, head := range heads {
+			if head != nil && (min < 0 || string(head.Key) < string(heads[min].Key)) {
+				min = i
+			}
+		}
+		if min < 0 {
+			break
+		}
+		if err := send(heads[min]); err != nil {
+			return err
+		}
+		resp, err := sources[min]()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		heads[min] = resp
+	}
+	return nil
+}
+
+// Latest versions of keys in the range stored on this server, sorted by key
+func (self *Server) scan(ctx context.Context, in *dbv2.ScanRequest) ([]*dbv2.GetResponse, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	start, end := string(in.StartKey), string(in.EndKey)
+	inRange := func(key string) bool {
+		return key >= start && (len(end) == 0 || key < end)
+	}
+
+	// Versions of each key in the range
+	versions := make(map[string][]*storage.Node)
+	table := tableOf(ctx)
+	left, right := table.ScanRange(start, end)
+	for _, r := range table.Ranges(self.Self) {
+		if r.Left > right || r.Right < left {
+			continue
+		}
+		if r.Left < left {
+			r.Left = left
+		}
+		if r.Right > right {
+			r.Right = right
+		}
+		store.Range(r.Left, r.Right, func(node *storage.Node) bool {
+			if !node.Chunk && inRange(node.Key) {
+				n := *node
+				versions[n.Key] = append(versions[n.Key], &n)
+			}
+			return true
+		})
+	}
+	keys := make([]string, 0, len(versions))
+	for key := range versions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	now := time.Now()
+	// Latest version of each key
+	latest := make(map[string]*storage.Node)
+	if in.Location == 0 {
+		for key, nodes := range versions {
+			var node *storage.Node
+			for _, n := range nodes {
+				if node == nil || n.CommitTime() > node.CommitTime() {
+					node = n
+				}
+			}
+			latest[key] = node
+		}
+	} else {
+		// Whether the first keys are known to be visible or not till the limit
+		next, visible := 0, uint32(0)
+		done := func() bool {
+			for ; next < len(keys) && (in.Limit == 0 || visible < in.Limit); next += 1 {
+				node, ok := latest[keys[next]]
+				if !ok {
+					return false
+				}
+				if !node.Deleted && !node.Expired(now) {
+					visible += 1
+				}
+			}
+			return true
+		}
+
+		// The first version of a key on the way to root is visible
+		node, err := self.getNode(ctx, in.Location)
+		if err != nil {
+			return nil, err
+		}
+		for !done() {
+			if _, ok := versions[node.Key]; ok && node.Location != 0 && !node.Chunk {
+				if _, found := latest[node.Key]; !found {
+					latest[node.Key] = node
+				}
+			}
+			if node.Dep == math.MaxUint64 {
+				break
+			}
+			child := node.Location
+			node, err = self.getNode(ctx, node.Dep)
+			if status.Code(err) == codes.NotFound {
+				return nil, status.Errorf(codes.DataLoss, "Dep of %x not found", child)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var results []*dbv2.GetResponse
+	for _, key := range keys {
+		if in.Limit > 0 && uint32(len(results)) >= in.Limit {
+			break
+		}
+		node, ok := latest[key]
+		if !ok || node.Deleted || node.Expired(now) {
+			continue
+		}
+		value, err := storage.Value(store, node)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &dbv2.GetResponse{
+			Value:    value,
+			Metadata: node.Metadata(),
+			Location: node.Location,
+			Key:      []byte(node.Key),
+		})
+	}
+	return results, nil
+}
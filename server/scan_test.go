@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+)
+
+func scanned(results []*dbv2.GetResponse) []string {
+	var keys []string
+	for _, resp := range results {
+		keys = append(keys, string(resp.Key)+"="+string(resp.Value))
+	}
+	return keys
+}
+
+func TestScanVisibleVersions(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	set := func(key, value string, dep uint64) uint64 {
+		return storage.Set(store, storage.Node{Key: key, Value: []byte(value), Dep: dep}, 0)
+	}
+
+	a1 := set("a", "1", 0)
+	b1 := set("b", "1", a1)
+	a2 := set("a", "2", b1)
+	deleted := storage.Delete(store, "b", a2)
+	c1 := set("c", "1", deleted)
+	d1 := set("d", "1", c1)
+	// Not visible from d1
+	set("a", "3", a2)
+	set("e", "1", a2)
+
+	for _, c := range []struct {
+		in   *dbv2.ScanRequest
+		want []string
+	}{
+		{&dbv2.ScanRequest{}, []string{"a=3", "c=1", "d=1", "e=1"}},
+		{&dbv2.ScanRequest{Location: d1}, []string{"a=2", "c=1", "d=1"}},
+		{&dbv2.ScanRequest{Location: d1, Limit: 2}, []string{"a=2", "c=1"}},
+		{&dbv2.ScanRequest{Location: d1, StartKey: []byte("b"), EndKey: []byte("d")}, []string{"c=1"}},
+		{&dbv2.ScanRequest{Location: c1, StartKey: []byte("b")}, []string{"c=1"}},
+	} {
+		results, err := s.scan(ctx, c.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys := scanned(results); !reflect.DeepEqual(keys, c.want) {
+			t.Fatalf("Scan of %v returns %v instead of %v", c.in, keys, c.want)
+		}
+	}
+}
+
+func TestMergeScanSortsSources(t *testing.T) {
+	source := func(keys ...string) func() (*dbv2.GetResponse, error) {
+		return func() (*dbv2.GetResponse, error) {
+			if len(keys) == 0 {
+				return nil, io.EOF
+			}
+			resp := &dbv2.GetResponse{Key: []byte(keys[0]), Value: []byte("1")}
+			keys = keys[1:]
+			return resp, nil
+		}
+	}
+
+	for limit, want := range map[uint32][]string{
+		0: {"a=1", "b=1", "c=1", "d=1", "e=1"},
+		3: {"a=1", "b=1", "c=1"},
+	} {
+		sources := []func() (*dbv2.GetResponse, error){source("b", "e"), source(), source("a", "c", "d")}
+		var results []*dbv2.GetResponse
+		err := mergeScan(sources, limit, func(resp *dbv2.GetResponse) error {
+			results = append(results, resp)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if keys := scanned(results); !reflect.DeepEqual(keys, want) {
+			t.Fatalf("Merged results with limit %d are %v instead of %v", limit, keys, want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *Server) scrubLoop() {
+	ticker := time.NewTicker(time.Second * time.Duration(s.ScrubInterval))
+	defer ticker.Stop()
+	for range ticker.C {
+		report := s.scrub()
+		if len(report.Corrupted)+len(report.Orphaned)+len(report.Dangling)+len(report.BadMappings) > 0 {
+			log.Printf("Scrub found %d corrupted, %d orphaned, %d dangling nodes and %d bad mappings\n",
+				len(report.Corrupted), len(report.Orphaned), len(report.Dangling), len(report.BadMappings))
+		}
+	}
+}
+
+// Check integrity of nodes owned by this server
+func (s *Server) scrub() *storage.ScrubReport {
+	// Avoid seeing nodes half removed by GC or splits
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	table := indexingService.Table()
+	ctx := withTable(context.Background(), table)
+	exists := func(loc uint64) bool {
+		_, err := s.getNode(ctx, loc)
+		// Only report nodes known to be missing
+		return status.Code(err) != codes.NotFound
+	}
+	report := storage.Scrub(store, table.Ranges(s.Self), exists)
+
+	// Debug
+	fmt.Println("[Scrub]")
+	fmt.Printf("Checked: %d, Corrupted: %d, Orphaned: %d, Dangling: %d, Bad mappings: %d\n",
+		report.Checked, len(report.Corrupted), len(report.Orphaned), len(report.Dangling), len(report.BadMappings))
+	return report
+}
+
+func (s *Server) Scrub(ctx context.Context, in *db.Empty) (*db.ScrubResponse, error) {
+	report := s.scrub()
+	return &db.ScrubResponse{
+		Checked:     int64(report.Checked),
+		Corrupted:   report.Corrupted,
+		Orphaned:    report.Orphaned,
+		Dangling:    report.Dangling,
+		BadMappings: report.BadMappings,
+	}, nil
+}
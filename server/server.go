@@ -3,18 +3,24 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
-	"math"
 	"math/rand"
 	"sync"
+	"time"
 
 	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
 	"github.com/DCsunset/openwhisk-grpc/indexing"
+	"github.com/DCsunset/openwhisk-grpc/latency"
 	"github.com/DCsunset/openwhisk-grpc/storage"
 	"github.com/DCsunset/openwhisk-grpc/utils"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
 )
 
 type Server struct {
@@ -24,20 +30,46 @@ type Server struct {
 	Self string `json:"self"`
 	// Initial server
 	Initial string `json:"initial"`
-	// Split threshold
+	// Split when the number of nodes exceeds it (0 to disable)
 	Threshold int `json:"threshold"`
+	// Split when the bytes used by nodes exceed it (0 to disable)
+	ByteThreshold int64 `json:"byteThreshold"`
+	// Persistence of the store
+	Storage storage.Options `json:"storage"`
+	// Retention policy of old versions
+	GC storage.RetentionPolicy `json:"gc"`
+	// Interval in seconds to remove expired nodes (0 to disable)
+	ExpiryInterval int `json:"expiryInterval"`
+	// Compress requests between servers with gzip
+	WireCompression bool `json:"wireCompression"`
+	// Interval in seconds to check integrity of nodes (0 to disable)
+	ScrubInterval int `json:"scrubInterval"`
+	// Simulated latency of storage operations
+	Latency latency.Config `json:"latency"`
+	// How keys are assigned to servers: range (default), ring or ordered
+	Partitioner string `json:"partitioner"`
+	// Virtual nodes per server in the hash ring
+	VirtualNodes int `json:"virtualNodes"`
 
 	lock                sync.RWMutex
+	partitionLock       sync.Mutex
+	nextTable           *indexing.Table // Including splits not rebalanced yet
+	mergeLock           sync.RWMutex    // Guards merge functions set by requests
 	mergeFunction       map[uint64]string
 	globalMergeFunction string
 }
 
-var store = storage.Store{}
+var store storage.Engine
 var indexingService = indexing.Service{}
 
+// Options to connect to other servers
+var dialOptions = []grpc.DialOption{
+	grpc.WithInsecure(),
+	grpc.WithUnaryInterceptor(forwardUnary),
+	grpc.WithStreamInterceptor(forwardStream),
+}
+
 func (s *Server) Init() {
-	store.Init()
-	indexingService.Init()
 	s.globalMergeFunction = ""
 	s.mergeFunction = make(map[uint64]string)
 
@@ -47,160 +79,382 @@ func (s *Server) Init() {
 		log.Fatalln(err)
 	}
 	json.Unmarshal(data, s)
+	// Flags override the configuration file
+	s.Latency.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	storage.Latency, err = latency.New(s.Latency)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	if s.WireCompression {
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)))
+	}
+
+	store, err = storage.NewEngine(s.Storage)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if snapshotter, ok := store.(storage.Snapshotter); ok {
+		s.mergeFunction, s.globalMergeFunction = snapshotter.MergeFunctions()
+	}
+
+	var partitioner indexing.Partitioner
+	switch s.Partitioner {
+	case "", "range":
+		partitioner = indexing.NewRangePartitioner(s.Initial)
+	case "ring":
+		// Servers not available to be used later are in the ring first
+		available := make(map[string]bool)
+		for _, server := range s.AvailableServers {
+			available[server] = true
+		}
+		ring := indexing.NewRing(s.VirtualNodes)
+		for _, server := range s.Servers {
+			if !available[server] {
+				ring.Add(server)
+			}
+		}
+		if len(ring.Tokens) == 0 {
+			log.Fatalf("Ring is empty as all servers %v are available servers\n", s.Servers)
+		}
+		partitioner = ring
+	case "ordered":
+		partitioner = indexing.NewOrderedPartitioner(s.Initial)
+	default:
+		log.Fatalf("Unknown partitioner %s\n", s.Partitioner)
+	}
+	indexingService.Init(partitioner)
+	storage.KeyHash = func(key string) uint32 {
+		return indexingService.Table().KeyHash(key)
+	}
 
-	// Use initial server first
-	indexingService.AddMapping(
-		0,
-		math.MaxUint32,
-		s.Initial,
-	)
+	if s.GC.Interval > 0 {
+		go s.gcLoop()
+	}
+	if s.ExpiryInterval > 0 {
+		go s.expiryLoop()
+	}
+	if spiller, ok := store.(storage.Spiller); ok && s.Storage.SpillAfter > 0 {
+		go s.spillLoop(spiller)
+	}
+	if s.ScrubInterval > 0 {
+		go s.scrubLoop()
+	}
 }
 
 func (self *Server) RemoveChildren(ctx context.Context, in *db.RemoveChildrenRequest) (*db.Empty, error) {
-	address := indexingService.Locate(utils.KeyHash(in.Location))
-
-	if address == self.Self {
+	if self.ownsLocation(ctx, in.Location) {
 		node := store.GetNode(in.Location)
+		if node == nil {
+			return &db.Empty{}, status.Errorf(codes.NotFound, "Location %x not found", in.Location)
+		}
+		keep := make(map[uint64]bool)
+		for _, child := range in.Keep {
+			keep[child] = true
+		}
+		var children []uint64
 		for _, child := range node.Children {
-			store.RemoveNode(child)
+			if keep[child] {
+				children = append(children, child)
+			} else {
+				storage.Remove(store, child)
+			}
 		}
-		node.Children = nil
+		store.SetChildren(in.Location, children)
 		return &db.Empty{}, nil
 	} else {
 		// Forward request to the correct server
-		conn, err := grpc.Dial(address, grpc.WithInsecure())
-		if err != nil {
-			return &db.Empty{}, err
-		}
-		defer conn.Close()
-		client := db.NewDbServiceClient(conn)
-
-		return client.RemoveChildren(ctx, in)
+		err := self.forwardLocation(ctx, in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			_, err := db.NewDbServiceClient(conn).RemoveChildren(ctx, in)
+			return err
+		})
+		return &db.Empty{}, err
 	}
 }
 
 func (self *Server) AddChild(ctx context.Context, in *db.AddChildRequest) (*db.Node, error) {
-	address := indexingService.Locate(utils.KeyHash(in.Location))
-
-	if address == self.Self {
+	if self.ownsLocation(ctx, in.Location) {
 		node := store.AddChild(in.Location, in.Child)
-		return &db.Node{
-			Location: node.Location,
-			Dep:      node.Dep,
-			Key:      node.Key,
-			Value:    node.Value,
-			Children: node.Children,
-		}, nil
+		if node == nil {
+			return &db.Node{}, status.Errorf(codes.NotFound, "Location %x not found", in.Location)
+		}
+		return node.Proto(), nil
 	} else {
 		// Forward request to the correct server
-		conn, err := grpc.Dial(address, grpc.WithInsecure())
-		if err != nil {
-			return &db.Node{}, err
-		}
-		defer conn.Close()
-		client := db.NewDbServiceClient(conn)
-
-		return client.AddChild(ctx, in)
+		node := &db.Node{}
+		err := self.forwardLocation(ctx, in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			node, err = db.NewDbServiceClient(conn).AddChild(ctx, in)
+			return err
+		})
+		return node, err
 	}
 }
 
 func (s *Server) Get(ctx context.Context, in *db.GetRequest) (*db.GetResponse, error) {
-	address := indexingService.LocateKey(in.Key)
-
-	if address == s.Self {
-		value, err := store.Get(in.Key, in.Location)
-		return &db.GetResponse{Value: value}, err
-	} else {
-		// Forward request to the correct server
-		conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if s.localGet(ctx, in.Key, in.Location) {
+		node, err := s.find(ctx, in.Key, in.Location, in.AsOf)
 		if err != nil {
 			return &db.GetResponse{}, err
 		}
-		defer conn.Close()
-		client := db.NewDbServiceClient(conn)
+		node.Value, err = storage.Value(store, node)
+		if err != nil {
+			return &db.GetResponse{}, err
+		}
+		n := node.Proto()
+		return &db.GetResponse{
+			Value:    n.Value,
+			Key:      n.Key,
+			Location: n.Location,
+		}, nil
+	} else {
+		// Forward request to the correct server
+		resp := &db.GetResponse{}
+		err := s.forwardGet(ctx, in.Key, in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			resp, err = db.NewDbServiceClient(conn).Get(ctx, in)
+			return err
+		})
+		return resp, err
+	}
+}
 
-		return client.Get(ctx, in)
+// Find the version read by a get request:
+// the version at loc if key is empty, or the latest version of key visible from loc
+// committed at or before asOf in unix nanoseconds (0 for now)
+func (s *Server) find(ctx context.Context, key string, loc uint64, asOf int64) (*storage.Node, error) {
+	lookup := func(loc uint64) (*storage.Node, error) {
+		return s.getNode(ctx, loc)
+	}
+	if len(key) == 0 {
+		return storage.GetVersion(lookup, loc)
+	}
+	var timestamp uint64
+	if asOf != 0 {
+		timestamp = storage.Timestamp(time.Unix(0, asOf))
+	}
+	return storage.Get(lookup, key, loc, timestamp)
+}
+
+// Nodes created by merge functions are moved to locations assigned by this server,
+// as merge functions do not know the key hash in use, and again on collision.
+// Dep of the nodes created on top of them are updated as well.
+func (self *Server) distributeNodes(ctx context.Context, nodes []*db.Node) {
+	table := tableOf(ctx)
+	add := make(map[string]func(n storage.Node) error)
+
+	for _, node := range nodes {
+		moveNode(node, nodes)
+	}
+	for _, node := range dependencyOrder(nodes) {
+		// Nodes created by merge functions are committed by this server
+		if node.Timestamp == 0 {
+			node.Timestamp = storage.Clock.Now()
+		}
+		server := table.Owner(node.Key, node.Location)
+		if _, ok := add[server]; !ok {
+			add[server] = self.addNode
+			if server != self.Self {
+				// Forward request to the correct server
+				conn, err := grpc.Dial(server, dialOptions...)
+				if err != nil {
+					log.Fatalln(err)
+				}
+				defer conn.Close()
+				client := dbv2.NewDbServiceClient(conn)
+				add[server] = func(n storage.Node) error {
+					// Verified by the receiving server
+					n.Checksum = n.ComputeChecksum()
+					_, err := client.AddNode(ctx, &dbv2.AddNodeRequest{
+						Node: n.ProtoV2(),
+					})
+					return err
+				}
+			}
+		}
+		for {
+			err := add[server](storage.NewNode(node))
+			if status.Code(err) != codes.AlreadyExists {
+				if err != nil {
+					log.Println(err)
+				}
+				break
+			}
+			moveNode(node, nodes)
+		}
 	}
 }
 
-func (self *Server) distributeNodes(nodes []*db.Node) {
-	nodeMapping := make(map[string][]*db.Node)
+// Move node to another location of its key and update Dep of nodes on top of it
+func moveNode(node *db.Node, nodes []*db.Node) {
+	old := node.Location
+	node.Location = storage.NewLocation(node.Key)
+	for _, n := range nodes {
+		if n.Dep == old {
+			n.Dep = node.Location
+		}
+	}
+}
 
+// Nodes with each node after the one at its Dep if both are in nodes
+func dependencyOrder(nodes []*db.Node) []*db.Node {
+	// Number of nodes at each location not ordered yet
+	pending := make(map[uint64]int)
 	for _, node := range nodes {
-		server := indexingService.Locate(utils.KeyHash(node.Location))
-		nodeMapping[server] = append(nodeMapping[server], node)
+		pending[node.Location] += 1
+	}
+	ordered := make([]*db.Node, 0, len(nodes))
+	added := make([]bool, len(nodes))
+	add := func(i int) {
+		added[i] = true
+		pending[nodes[i].Location] -= 1
+		ordered = append(ordered, nodes[i])
+	}
+	for len(ordered) < len(nodes) {
+		cycle := true
+		for i, node := range nodes {
+			if !added[i] && pending[node.Dep] == 0 {
+				add(i)
+				cycle = false
+			}
+		}
+		if cycle {
+			// The rest depend on each other
+			for i := range nodes {
+				if !added[i] {
+					add(i)
+				}
+			}
+		}
 	}
+	return ordered
+}
 
-	ctx := context.Background()
-	for server, nodes := range nodeMapping {
-		// Forward request to the correct server
-		conn, err := grpc.Dial(server, grpc.WithInsecure())
-		if err != nil {
-			log.Fatalln(err)
+// Replace the children of parent with the nodes returned by its merge function.
+// Children returned unchanged (such as tombstones) keep their locations.
+func (s *Server) applyMerge(ctx context.Context, parent *db.Node, nodes []*db.Node) {
+	existing := make(map[uint64]bool)
+	for _, child := range parent.Children {
+		existing[child] = true
+	}
+	var keep []uint64
+	var created []*db.Node
+	for _, node := range nodes {
+		if existing[node.Location] {
+			keep = append(keep, node.Location)
+		} else {
+			created = append(created, node)
 		}
-		defer conn.Close()
-		client := db.NewDbServiceClient(conn)
-		for _, node := range nodes {
-			client.AddNode(ctx, &db.AddNodeRequest{
-				Node: node,
-			})
+	}
+
+	s.distributeNodes(ctx, created)
+
+	// Remove current children and use new children
+	s.RemoveChildren(ctx, &db.RemoveChildrenRequest{
+		Location: parent.Location,
+		Keep:     keep,
+	})
+	for _, child := range created {
+		s.AddChild(ctx, &db.AddChildRequest{
+			Location: child.Dep,
+			Child:    child.Location,
+		})
+	}
+}
+
+func (s *Server) Set(ctx context.Context, in *db.SetRequest) (*db.SetResponse, error) {
+	create := func() uint64 {
+		node := storage.Node{
+			Key:   in.Key,
+			Value: []byte(in.Value),
+			Dep:   in.Dep,
 		}
+		return storage.Set(store, node, time.Second*time.Duration(in.TTL))
+	}
+	forward := func(conn *grpc.ClientConn) (uint64, error) {
+		resp, err := db.NewDbServiceClient(conn).Set(ctx, in)
+		return resp.GetLocation(), err
 	}
+	loc, err := s.write(ctx, in.Key, in.Dep, create, forward)
+	return &db.SetResponse{Location: loc}, err
 }
 
-func (s *Server) Set(ctx context.Context, in *db.SetRequest) (result *db.SetResponse, err error) {
+// Write a tombstone so that the key is not found from its descendants
+func (s *Server) Delete(ctx context.Context, in *db.DeleteRequest) (*db.SetResponse, error) {
+	create := func() uint64 {
+		return storage.Delete(store, in.Key, in.Dep)
+	}
+	forward := func(conn *grpc.ClientConn) (uint64, error) {
+		resp, err := db.NewDbServiceClient(conn).Delete(ctx, in)
+		return resp.GetLocation(), err
+	}
+	loc, err := s.write(ctx, in.Key, in.Dep, create, forward)
+	return &db.SetResponse{Location: loc}, err
+}
+
+// Create a new version of key locally by create or forward the request to the owner of key
+func (s *Server) write(
+	ctx context.Context,
+	key string,
+	dep uint64,
+	create func() uint64,
+	forward func(conn *grpc.ClientConn) (uint64, error),
+) (loc uint64, err error) {
 	s.lock.RLock()
 	defer s.lock.RUnlock()
 
-	address := indexingService.LocateKey(in.Key)
+	// Nodes are moved with the lock held, so route by the table in use after them
+	ctx = withTable(ctx, indexingService.Table())
+	address := tableOf(ctx).LocateKey(key)
 
 	if address == s.Self {
-		loc := store.Set(in.Key, in.Value, in.Dep)
+		if dep != 0 {
+			// Commit after the parent even if its server is ahead
+			parent, err := s.getNode(ctx, dep)
+			if err != nil {
+				return 0, err
+			}
+			storage.Clock.Update(parent.Timestamp)
+		}
+		loc = create()
 		// Add child
-		if in.Dep != 0 {
-			parent, _ := s.AddChild(ctx, &db.AddChildRequest{
-				Location: in.Dep,
+		if dep != 0 {
+			parent, err := s.AddChild(ctx, &db.AddChildRequest{
+				Location: dep,
 				Child:    loc,
 			})
+			if err != nil {
+				// Remove the version not linked to its parent before retrying
+				storage.Remove(store, loc)
+				return 0, err
+			}
 
 			// Trigger function if there's conflict
 			if len(parent.Children) > 1 {
-				merge, ok := s.mergeFunction[in.Dep]
-				if !ok {
-					merge = s.globalMergeFunction
-				}
+				merge := s.mergeFunctionOf(dep)
 				if len(merge) > 0 {
 					params, _ := json.Marshal(parent)
 					resp := utils.CallAction(merge, params)
 					var children *db.Nodes
 					err := json.Unmarshal(resp, &children)
 					if err != nil {
-						return &db.SetResponse{Location: loc}, err
+						return loc, err
 					}
 
-					s.distributeNodes(children.Nodes)
-
-					// Remove current children and use new children
-					s.RemoveChildren(ctx, &db.RemoveChildrenRequest{
-						Location: parent.Location,
-					})
-					for _, child := range children.Nodes {
-						s.AddChild(ctx, &db.AddChildRequest{
-							Location: child.Dep,
-							Child:    child.Location,
-						})
-					}
+					s.applyMerge(ctx, parent, children.Nodes)
 
 					// Debug
 					fmt.Println("[Merge]")
-					indexingService.Print()
-					fmt.Printf("Nodes: %d\n", store.Size)
+					tableOf(ctx).Print()
+					fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
 					// store.Print()
 				}
 			}
 		}
 
-		if store.Size > s.Threshold && len(s.AvailableServers) > 0 {
+		if s.needSplit() {
 			s.lock.RUnlock()
 			s.lock.Lock()
 			s.splitRange()
@@ -208,18 +462,28 @@ func (s *Server) Set(ctx context.Context, in *db.SetRequest) (result *db.SetResp
 			s.lock.RLock()
 		}
 
-		result = &db.SetResponse{Location: loc}
+		if snapshotter, ok := store.(storage.Snapshotter); ok && snapshotter.NeedSnapshot() {
+			s.lock.RUnlock()
+			s.lock.Lock()
+			if err := snapshotter.Snapshot(); err != nil {
+				log.Println(err)
+			}
+			s.lock.Unlock()
+			s.lock.RLock()
+		}
 	} else {
 		// Forward request to the correct server
-		conn, err := grpc.Dial(address, grpc.WithInsecure())
+		conn, err := grpc.Dial(address, dialOptions...)
 		if err != nil {
-			return &db.SetResponse{}, err
+			return 0, err
 		}
 		defer conn.Close()
-		client := db.NewDbServiceClient(conn)
 
-		result, err = client.Set(ctx, in)
-		if store.Size > s.Threshold && len(s.AvailableServers) > 0 {
+		loc, err = forward(conn)
+		if err != nil {
+			return loc, err
+		}
+		if s.needSplit() {
 			s.lock.RUnlock()
 			s.lock.Lock()
 			s.splitRange()
@@ -230,52 +494,79 @@ func (s *Server) Set(ctx context.Context, in *db.SetRequest) (result *db.SetResp
 
 	// Debug
 	fmt.Println("[Set]")
-	indexingService.Print()
-	fmt.Printf("Nodes: %d\n", store.Size)
+	tableOf(ctx).Print()
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
 	// store.Print()
-	return result, nil
+	return loc, nil
 }
 
-// [l, m] [m+1, r]
 func (s *Server) Split(ctx context.Context, in *db.SplitRequest) (*db.Empty, error) {
-	indexingService.RemoveMapping(in.Left, in.Right)
-	indexingService.AddMapping(in.Left, in.Mid, in.LeftServer)
-	indexingService.AddMapping(in.Mid+1, in.Right, in.RightServer)
-
-	// Remove from available servers
-	for i, server := range s.AvailableServers {
-		if server == in.LeftServer || server == in.RightServer {
-			l := len(s.AvailableServers)
-			s.AvailableServers[i] = s.AvailableServers[l-1]
-			s.AvailableServers = s.AvailableServers[:l-1]
-			break
-		}
+	next, err := decodeTable(in.Table)
+	if err != nil {
+		return &db.Empty{}, err
+	}
+	if s.adoptTable(next) {
+		// The caller might hold the lock while waiting for this server
+		go s.rebalance()
 	}
 
 	// Debug
 	fmt.Println("[Split]")
-	indexingService.Print()
-	fmt.Printf("Nodes: %d\n", store.Size)
+	next.Print()
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
 	// store.Print()
 
 	return &db.Empty{}, nil
 }
 
-// Split based on key range
-// FIXME: multiple servers might split at the same
-func (s *Server) splitRange() {
-	left, right := indexingService.Range(s.Self)
-	if left == right {
-		return
+// Whether the store has exceeded any split threshold
+func (s *Server) needSplit() bool {
+	s.partitionLock.Lock()
+	available := len(s.AvailableServers)
+	s.partitionLock.Unlock()
+	if available == 0 {
+		return false
 	}
+	return (s.Threshold > 0 && store.Count() > s.Threshold) ||
+		(s.ByteThreshold > 0 && store.Bytes() > s.ByteThreshold)
+}
 
-	number := len(s.AvailableServers)
-	if number == 0 {
-		return
-	}
-	server := s.AvailableServers[rand.Intn(number)]
+// Move part of the keys to an available server as proposed by the partitioner
+func (s *Server) splitRange() {
+	s.changeTable(func(current *indexing.Table) (indexing.Split, bool) {
+		s.partitionLock.Lock()
+		available := append([]string(nil), s.AvailableServers...)
+		s.partitionLock.Unlock()
+		number := len(available)
+		if number == 0 {
+			return indexing.Split{}, false
+		}
+		server := available[rand.Intn(number)]
 
-	conn, err := grpc.Dial(server, grpc.WithInsecure())
+		var samples []indexing.Sample
+		for _, r := range current.Ranges(s.Self) {
+			store.Range(r.Left, r.Right, func(node *storage.Node) bool {
+				samples = append(samples, indexing.Sample{Key: node.Key, KeyHash: utils.KeyHash(node.Location)})
+				return true
+			})
+		}
+
+		// Debug
+		fmt.Println("[SplitRange]")
+		utils.Print(available)
+		fmt.Println()
+		fmt.Printf("Address: %s\n", server)
+
+		return current.ProposeSplit(s.Self, server, samples)
+	})
+}
+
+// Apply the split proposed on top of the latest table and broadcast the table.
+// Splits take the indexing lock of the initial server in turn
+// so that each of them extends the table of the last one.
+// Return whether the table is changed.
+func (s *Server) changeTable(propose func(current *indexing.Table) (indexing.Split, bool)) bool {
+	conn, err := grpc.Dial(s.Initial, dialOptions...)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -288,166 +579,163 @@ func (s *Server) splitRange() {
 		log.Fatalln(err)
 	}
 	if !resp.Success {
-		return
+		return false
 	}
+	defer func() {
+		_, err := client.SetIndexingLock(ctx, &db.SetIndexingLockRequest{Lock: false})
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}()
 
-	mid := uint32((uint64(left) + uint64(right)) / 2)
+	latest, err := decodeTable(resp.Table)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if s.adoptTable(latest) {
+		// Missed splits by other servers, which might have taken the keys to split
+		s.switchNextTable()
+		return false
+	}
 
-	var keys []uint32
-	for i, node := range store.Nodes {
-		if i == 0 || node.Key == "" {
-			continue
-		}
-		keys = append(keys, utils.KeyHash(node.Location))
+	// Split on top of splits not rebalanced yet
+	s.partitionLock.Lock()
+	current := s.latestTable()
+	s.partitionLock.Unlock()
+	split, ok := propose(current)
+	if !ok {
+		return false
+	}
+	next := &indexing.Table{Partitioner: current.Clone(), Epoch: current.Epoch + 1}
+	next.ApplySplit(split)
+	table, err := encodeTable(next)
+	if err != nil {
+		log.Fatalln(err)
 	}
 
-	le := 0
-	greater := 0
-	for _, key := range keys {
-		if key > mid {
-			greater += 1
-		} else if key <= mid {
-			le += 1
-		}
+	// Transfer nodes before other servers route to their new owners
+	moving := s.adoptTable(next)
+	var moved []*storage.Node
+	if moving {
+		moved = s.transfer(next)
 	}
 
-	// Debug
-	fmt.Println("[SplitRange]")
-	utils.Print(s.AvailableServers)
-	fmt.Println()
-
-	var leftServer, rightServer string
-	var results []*db.Node
-	if greater >= le {
-		i := 0
-		for _, node := range store.Nodes {
-			if node.Key == "" {
-				continue
-			}
-			if keys[i] <= mid {
-				results = append(results, &db.Node{
-					Location: node.Location,
-					Dep:      node.Dep,
-					Key:      node.Key,
-					Value:    node.Value,
-					Children: node.Children,
-				})
-			}
-			i += 1
+	// Update indexing server
+	request := &db.SplitRequest{
+		Left:        split.Left,
+		Right:       split.Right,
+		Mid:         split.Mid,
+		LeftServer:  split.LeftServer,
+		RightServer: split.RightServer,
+		Join:        split.Join,
+		Table:       table,
+		Key:         split.Key,
+		Leave:       split.Leave,
+	}
+	for _, addr := range s.Servers {
+		if addr == s.Self {
+			continue
 		}
-		rightServer = s.Self
-		leftServer = server
-	} else {
-		i := 0
-		for _, node := range store.Nodes {
-			if node.Key == "" {
-				continue
-			}
-			if keys[i] > mid {
-				results = append(results, &db.Node{
-					Location: node.Location,
-					Dep:      node.Dep,
-					Key:      node.Key,
-					Value:    node.Value,
-					Children: node.Children,
-				})
-			}
-			i += 1
+		// Forward request to all servers
+		conn, err := grpc.Dial(addr, dialOptions...)
+		if err != nil {
+			log.Fatalln(err)
 		}
-		rightServer = server
-		leftServer = s.Self
-	}
-
-	// Debug
-	fmt.Printf("AddNodes: %d\n", len(results))
-	fmt.Printf("Address: %s\n", server)
-
-	for _, node := range results {
-		_, err = client.AddNode(ctx, &db.AddNodeRequest{
-			Node: node,
-		})
+		defer conn.Close()
+		_, err = db.NewDbServiceClient(conn).Split(ctx, request)
 		if err != nil {
 			log.Fatalln(err)
 		}
 	}
 
-	// Transfer merge function
-	for _, node := range results {
-		f, ok := s.mergeFunction[node.Location]
-		if ok {
-			_, err := client.SetMergeFunction(ctx, &db.SetMergeFunctionRequest{
-				Location: node.Location,
-				Name:     f,
-			})
-			if err != nil {
-				log.Fatalln(err)
-			}
-			delete(s.mergeFunction, node.Location)
-		}
+	// Remove nodes after range has been updated
+	if moving {
+		s.switchTable(next, moved)
 	}
+	return true
+}
 
-	// Update indexing server
-	request := &db.SplitRequest{
-		Left:        left,
-		Right:       right,
-		Mid:         mid,
-		LeftServer:  leftServer,
-		RightServer: rightServer,
+func (s *Server) Leave(ctx context.Context, in *db.Empty) (*db.Empty, error) {
+	if s.Partitioner != "ring" {
+		return &db.Empty{}, status.Errorf(codes.FailedPrecondition, "Only servers of a hash ring can leave")
 	}
 
-	for _, addr := range s.Servers {
-		if addr == s.Self {
-			_, err := s.Split(ctx, request)
-			if err != nil {
-				log.Fatalln(err)
-			}
-		} else if addr == server {
-			_, err := client.Split(ctx, request)
-			if err != nil {
-				log.Fatalln(err)
-			}
-		} else {
-			// Forward request to all servers
-			conn, err := grpc.Dial(addr, grpc.WithInsecure())
-			if err != nil {
-				log.Fatalln(err)
-			}
-			defer conn.Close()
-			client := db.NewDbServiceClient(conn)
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
-			_, err = client.Split(context.Background(), request)
-			if err != nil {
-				log.Fatalln(err)
-			}
+	// Another server has to take the keys
+	inRing := func(current *indexing.Table) bool {
+		others := false
+		for _, addr := range s.Servers {
+			others = others || (addr != s.Self && len(current.Ranges(addr)) > 0)
 		}
+		return others && len(current.Ranges(s.Self)) > 0
 	}
-
-	// Remove nodes after range has been updated
-	for _, node := range results {
-		store.RemoveNode(node.Location)
+	s.partitionLock.Lock()
+	current := s.latestTable()
+	s.partitionLock.Unlock()
+	if !inRing(current) {
+		return &db.Empty{}, status.Errorf(codes.FailedPrecondition, "Server %s is not in a ring with other servers", s.Self)
 	}
 
-	_, err = client.SetIndexingLock(ctx, &db.SetIndexingLockRequest{
-		Lock: false,
+	left := s.changeTable(func(current *indexing.Table) (indexing.Split, bool) {
+		return indexing.Split{Leave: s.Self}, inRing(current)
 	})
-	if err != nil {
-		log.Fatalln(err)
+	if !left {
+		return &db.Empty{}, status.Errorf(codes.Unavailable, "Routing table is changing")
 	}
+
+	// Debug
+	fmt.Println("[Leave]")
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+	return &db.Empty{}, nil
 }
 
 func (s *Server) AddNode(ctx context.Context, in *db.AddNodeRequest) (*db.Empty, error) {
-	store.AddNode(in.Node)
+	existing := store.GetNode(in.Node.Location)
+	if existing != nil && existing.SameContentV1(in.Node) {
+		// Same node sent again, keeping the value which might not be valid UTF-8
+		store.SetChildren(in.Node.Location, in.Node.Children)
+		return &db.Empty{}, nil
+	}
+	return &db.Empty{}, s.addNode(storage.NewNode(in.Node))
+}
+
+// Add a node unless another node has taken its location
+func (s *Server) addNode(node storage.Node) error {
+	if node.Timestamp != 0 {
+		storage.Clock.Update(node.Timestamp)
+	} else if node.Created == 0 {
+		// Commit nodes from older clients now.
+		// Nodes written before timestamps were recorded keep committing at Created.
+		node.Timestamp = storage.Clock.Now()
+	}
+	if !store.PutNewNode(node) {
+		existing := store.GetNode(node.Location)
+		if existing != nil && !existing.SameContent(&node) {
+			return status.Errorf(codes.AlreadyExists, "Location %x is taken by another node", node.Location)
+		}
+		// Same node sent again, which might have new children
+		store.PutNode(node)
+	}
+
 	// Debug
 	fmt.Println("[AddNodes]")
-	indexingService.Print()
-	fmt.Printf("Nodes: %d\n", store.Size)
-
-	return &db.Empty{}, nil
+	indexingService.Table().Print()
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+	return nil
 }
 
 func (self *Server) SetMergeFunction(ctx context.Context, in *db.SetMergeFunctionRequest) (*db.Empty, error) {
 	// FIXME: find the right server to add merge function
 
+	self.mergeLock.Lock()
+	defer self.mergeLock.Unlock()
+	if snapshotter, ok := store.(storage.Snapshotter); ok {
+		if err := snapshotter.SetMergeFunction(in.Location, in.Name); err != nil {
+			return &db.Empty{}, err
+		}
+	}
 	if len(in.Name) == 0 {
 		delete(self.mergeFunction, in.Location)
 	} else {
@@ -456,13 +744,36 @@ func (self *Server) SetMergeFunction(ctx context.Context, in *db.SetMergeFunctio
 	return &db.Empty{}, nil
 }
 
+// Merge function called on conflicts among the children of loc
+func (s *Server) mergeFunctionOf(loc uint64) string {
+	s.mergeLock.RLock()
+	defer s.mergeLock.RUnlock()
+	if f, ok := s.mergeFunction[loc]; ok {
+		return f
+	}
+	return s.globalMergeFunction
+}
+
+// Copy of the merge functions of locations
+func (s *Server) mergeFunctions() map[uint64]string {
+	s.mergeLock.RLock()
+	defer s.mergeLock.RUnlock()
+	functions := make(map[uint64]string, len(s.mergeFunction))
+	for loc, f := range s.mergeFunction {
+		functions[loc] = f
+	}
+	return functions
+}
+
 func (self *Server) SetGlobalMergeFunction(ctx context.Context, in *db.SetGlobalMergeFunctionRequest) (*db.Empty, error) {
 	for _, addr := range self.Servers {
 		if addr == self.Self {
-			self.globalMergeFunction = in.Name
+			if err := self.setGlobalMergeFunction(in.Name); err != nil {
+				return &db.Empty{}, err
+			}
 		} else {
 			// Forward request to all servers
-			conn, err := grpc.Dial(addr, grpc.WithInsecure())
+			conn, err := grpc.Dial(addr, dialOptions...)
 			if err != nil {
 				log.Fatalln(err)
 			}
@@ -475,51 +786,114 @@ func (self *Server) SetGlobalMergeFunction(ctx context.Context, in *db.SetGlobal
 	return &db.Empty{}, nil
 }
 
-func (self *Server) GetNode(ctx context.Context, in *db.GetNodeRequest) (*db.Node, error) {
-	address := indexingService.Locate(utils.KeyHash(in.Location))
+func (s *Server) setGlobalMergeFunction(name string) error {
+	s.mergeLock.Lock()
+	defer s.mergeLock.Unlock()
+	if snapshotter, ok := store.(storage.Snapshotter); ok {
+		if err := snapshotter.SetGlobalMergeFunction(name); err != nil {
+			return err
+		}
+	}
+	s.globalMergeFunction = name
+	return nil
+}
+
+func (s *Server) Snapshot(ctx context.Context, in *db.Empty) (*db.SnapshotResponse, error) {
+	snapshotter, ok := store.(storage.Snapshotter)
+	if !ok {
+		return &db.SnapshotResponse{}, status.Errorf(codes.Unimplemented, "Storage engine does not support snapshots")
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
 
-	if address == self.Self {
+	if err := snapshotter.Snapshot(); err != nil {
+		return &db.SnapshotResponse{}, err
+	}
+	// Debug
+	fmt.Println("[Snapshot]")
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+
+	return &db.SnapshotResponse{Size: int64(store.Count())}, nil
+}
+
+func (self *Server) GetNode(ctx context.Context, in *db.GetNodeRequest) (*db.Node, error) {
+	if self.ownsLocation(ctx, in.Location) {
 		node := store.GetNode(in.Location)
 		if node == nil {
-			return &db.Node{}, fmt.Errorf("Location %x not found", in.Location)
+			return &db.Node{}, status.Errorf(codes.NotFound, "Location %x not found", in.Location)
+		}
+		if err := node.Verify(); err != nil {
+			return &db.Node{}, err
 		}
 
-		return &db.Node{
-			Location: node.Location,
-			Dep:      node.Dep,
-			Key:      node.Key,
-			Value:    node.Value,
-			Children: node.Children,
-		}, nil
+		return node.Proto(), nil
 	} else {
 		// Forward request to the correct server
-		conn, err := grpc.Dial(address, grpc.WithInsecure())
-		if err != nil {
-			return &db.Node{}, err
+		node := &db.Node{}
+		err := self.forwardLocation(ctx, in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			node, err = db.NewDbServiceClient(conn).GetNode(ctx, in)
+			return err
+		})
+		return node, err
+	}
+}
+
+func (self *Server) Relink(ctx context.Context, in *db.RelinkRequest) (*db.Empty, error) {
+	if self.ownsLocation(ctx, in.Location) {
+		node := store.GetNode(in.Location)
+		if node == nil {
+			return &db.Empty{}, status.Errorf(codes.NotFound, "Location %x not found", in.Location)
 		}
-		defer conn.Close()
-		client := db.NewDbServiceClient(conn)
 
-		return client.GetNode(ctx, in)
+		updated := *node
+		if updated.Dep == in.Removed {
+			updated.Dep = in.Dep
+		}
+		updated.Children = nil
+		for _, child := range node.Children {
+			if child == in.Removed {
+				updated.Children = append(updated.Children, in.Children...)
+			} else {
+				updated.Children = append(updated.Children, child)
+			}
+		}
+		store.PutNode(updated)
+		return &db.Empty{}, nil
+	} else {
+		// Forward request to the correct server
+		err := self.forwardLocation(ctx, in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			_, err := db.NewDbServiceClient(conn).Relink(ctx, in)
+			return err
+		})
+		return &db.Empty{}, err
 	}
 }
 
 func (self *Server) SetIndexingLock(ctx context.Context, in *db.SetIndexingLockRequest) (*db.SetIndexingLockResponse, error) {
-	if in.Lock {
-		if !indexingService.Lock {
-			indexingService.Lock = true
-			return &db.SetIndexingLockResponse{
-				Success: true,
-			}, nil
-		} else {
-			return &db.SetIndexingLockResponse{
-				Success: false,
-			}, nil
-		}
-	} else {
-		indexingService.Lock = false
+	if !in.Lock {
+		indexingService.Unlock()
 		return &db.SetIndexingLockResponse{
 			Success: true,
 		}, nil
 	}
+	if !indexingService.TryLock() {
+		return &db.SetIndexingLockResponse{
+			Success: false,
+		}, nil
+	}
+
+	// Splits by the lock holder extend the latest table
+	self.partitionLock.Lock()
+	table, err := encodeTable(self.latestTable())
+	self.partitionLock.Unlock()
+	if err != nil {
+		indexingService.Unlock()
+		return &db.SetIndexingLockResponse{}, err
+	}
+	return &db.SetIndexingLockResponse{
+		Success: true,
+		Table:   table,
+	}, nil
 }
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The v2 API with byte values and metadata sharing the store with v1
+type ServerV2 struct {
+	server *Server
+}
+
+func (self *ServerV2) Get(ctx context.Context, in *dbv2.GetRequest) (*dbv2.GetResponse, error) {
+	if self.server.localGet(ctx, string(in.Key), in.Location) {
+		node, err := self.server.find(ctx, string(in.Key), in.Location, in.AsOf)
+		if err != nil {
+			return &dbv2.GetResponse{}, err
+		}
+		node.Value, err = storage.Value(store, node)
+		if err != nil {
+			return &dbv2.GetResponse{}, err
+		}
+		return &dbv2.GetResponse{
+			Value:    node.Value,
+			Metadata: node.Metadata(),
+			Location: node.Location,
+			Key:      []byte(node.Key),
+		}, nil
+	} else {
+		// Forward request to the correct server
+		resp := &dbv2.GetResponse{}
+		err := self.server.forwardGet(ctx, string(in.Key), in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			resp, err = dbv2.NewDbServiceClient(conn).Get(ctx, in)
+			return err
+		})
+		return resp, err
+	}
+}
+
+func (self *ServerV2) Set(ctx context.Context, in *dbv2.SetRequest) (*dbv2.SetResponse, error) {
+	create := func() uint64 {
+		node := storage.Node{
+			Key:         string(in.Key),
+			Value:       in.Value,
+			Dep:         in.Dep,
+			ContentType: in.ContentType,
+		}
+		return storage.Set(store, node, time.Second*time.Duration(in.TTL))
+	}
+	forward := func(conn *grpc.ClientConn) (uint64, error) {
+		resp, err := dbv2.NewDbServiceClient(conn).Set(ctx, in)
+		return resp.GetLocation(), err
+	}
+	loc, err := self.server.write(ctx, string(in.Key), in.Dep, create, forward)
+	return &dbv2.SetResponse{Location: loc}, err
+}
+
+func (self *ServerV2) Delete(ctx context.Context, in *dbv2.DeleteRequest) (*dbv2.SetResponse, error) {
+	create := func() uint64 {
+		return storage.Delete(store, string(in.Key), in.Dep)
+	}
+	forward := func(conn *grpc.ClientConn) (uint64, error) {
+		resp, err := dbv2.NewDbServiceClient(conn).Delete(ctx, in)
+		return resp.GetLocation(), err
+	}
+	loc, err := self.server.write(ctx, string(in.Key), in.Dep, create, forward)
+	return &dbv2.SetResponse{Location: loc}, err
+}
+
+func (self *ServerV2) PutStream(stream dbv2.DbService_PutStreamServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	address := tableOf(ctx).LocateKey(string(first.Key))
+
+	if address != self.server.Self {
+		// Forward the stream to the correct server
+		conn, err := grpc.Dial(address, dialOptions...)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		client, err := dbv2.NewDbServiceClient(conn).PutStream(ctx)
+		if err != nil {
+			return err
+		}
+		for in := first; ; {
+			if err := client.Send(in); err != nil {
+				return err
+			}
+			in, err = stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		resp, err := client.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		return stream.SendAndClose(resp)
+	}
+
+	// Store chunks before creating the version
+	writer := storage.NewChunkWriter(store, string(first.Key), self.server.Storage.ChunkSize)
+	for in := first; ; {
+		writer.Write(in.Data)
+		in, err = stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writer.Abort()
+			return err
+		}
+	}
+	next, length := writer.Close()
+
+	create := func() uint64 {
+		node := storage.Node{
+			Key:         string(first.Key),
+			Dep:         first.Dep,
+			ContentType: first.ContentType,
+			Next:        next,
+			Length:      length,
+		}
+		return storage.Set(store, node, time.Second*time.Duration(first.TTL))
+	}
+	forward := func(conn *grpc.ClientConn) (uint64, error) {
+		return 0, status.Errorf(codes.Unavailable, "Key %s moved during the stream", first.Key)
+	}
+	loc, err := self.server.write(ctx, string(first.Key), first.Dep, create, forward)
+	if err != nil {
+		// The version is not created so its chunks are unreachable
+		storage.RemoveChunks(store, &storage.Node{Next: next})
+		return err
+	}
+	return stream.SendAndClose(&dbv2.SetResponse{Location: loc})
+}
+
+func (self *ServerV2) GetStream(in *dbv2.GetRequest, stream dbv2.DbService_GetStreamServer) error {
+	if !self.server.localGet(stream.Context(), string(in.Key), in.Location) {
+		// Forward the stream from the correct server
+		return self.server.forwardGet(stream.Context(), string(in.Key), in.Location, func(ctx context.Context, conn *grpc.ClientConn) error {
+			client, err := dbv2.NewDbServiceClient(conn).GetStream(ctx, in)
+			if err != nil {
+				return err
+			}
+			for {
+				resp, err := client.Recv()
+				if err == io.EOF {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				if err := stream.Send(resp); err != nil {
+					return err
+				}
+			}
+		})
+	}
+
+	node, err := self.server.find(stream.Context(), string(in.Key), in.Location, in.AsOf)
+	if err != nil {
+		return err
+	}
+	// Send metadata with the first part
+	resp := &dbv2.GetStreamResponse{
+		Metadata: node.Metadata(),
+		Location: node.Location,
+		Key:      []byte(node.Key),
+	}
+	size := self.server.Storage.ChunkSize
+	if size <= 0 {
+		size = storage.DefaultChunkSize
+	}
+	err = storage.ReadValue(store, node, func(data []byte) error {
+		for len(data) > 0 || resp != nil {
+			if resp == nil {
+				resp = &dbv2.GetStreamResponse{}
+			}
+			l := len(data)
+			if l > size {
+				l = size
+			}
+			resp.Data = data[:l]
+			data = data[l:]
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+			resp = nil
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if resp != nil {
+		// Empty value
+		return stream.Send(resp)
+	}
+	return nil
+}
+
+func (self *ServerV2) GetNode(ctx context.Context, in *dbv2.GetNodeRequest) (*dbv2.Node, error) {
+	node, err := self.server.getNode(ctx, in.Location)
+	if err != nil {
+		return &dbv2.Node{}, err
+	}
+	if err := node.Decompress(); err != nil {
+		return &dbv2.Node{}, err
+	}
+	return node.ProtoV2(), nil
+}
+
+func (self *ServerV2) AddNode(ctx context.Context, in *dbv2.AddNodeRequest) (*dbv2.Empty, error) {
+	node := storage.NewNodeV2(in.Node)
+	if err := node.Verify(); err != nil {
+		return &dbv2.Empty{}, err
+	}
+	return &dbv2.Empty{}, self.server.addNode(node)
+}
+
+func (self *ServerV2) History(in *dbv2.HistoryRequest, stream dbv2.DbService_HistoryServer) error {
+	ctx := stream.Context()
+	node, err := self.server.getNode(ctx, in.Location)
+	if err != nil {
+		return err
+	}
+
+	// Follow Dep till root, reading each node from the server owning it
+	sent := uint32(0)
+	for node.Location != 0 && (in.Limit == 0 || sent < in.Limit) {
+		if !node.Chunk && (len(in.Key) == 0 || node.Key == string(in.Key)) {
+			if err := node.Decompress(); err != nil {
+				return err
+			}
+			if err := stream.Send(node.ProtoV2()); err != nil {
+				return err
+			}
+			sent += 1
+		}
+		child := node.Location
+		node, err = self.server.getNode(ctx, node.Dep)
+		if status.Code(err) == codes.NotFound {
+			return status.Errorf(codes.DataLoss, "Dep of %x not found", child)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (self *ServerV2) Heads(ctx context.Context, in *dbv2.HeadsRequest) (*dbv2.HeadsResponse, error) {
+	if in.Location == 0 {
+		// Versions without Dep are not added as children of root
+		return &dbv2.HeadsResponse{}, status.Errorf(codes.InvalidArgument, "Heads of root are not tracked")
+	}
+	key := string(in.Key)
+	var heads []uint64
+	visited := make(map[uint64]bool)
+
+	// Return whether a version of key is in the subtree of loc
+	var walk func(loc uint64) (bool, error)
+	walk = func(loc uint64) (bool, error) {
+		if visited[loc] {
+			return false, nil
+		}
+		visited[loc] = true
+		node, err := self.server.getNode(ctx, loc)
+		if err != nil {
+			return false, err
+		}
+
+		found := false
+		for _, child := range node.Children {
+			ok, err := walk(child)
+			if err != nil {
+				return false, err
+			}
+			found = found || ok
+		}
+		version := loc != 0 && (len(key) == 0 || node.Key == key)
+		// A head has no newer version on its branch
+		if version && !found {
+			heads = append(heads, loc)
+		}
+		return found || version, nil
+	}
+
+	if _, err := walk(in.Location); err != nil {
+		return &dbv2.HeadsResponse{}, err
+	}
+	return &dbv2.HeadsResponse{Locations: heads}, nil
+}
+
+// Get a node from the server owning it
+func (self *Server) getNode(ctx context.Context, loc uint64) (*storage.Node, error) {
+	if self.ownsLocation(ctx, loc) {
+		node := store.GetNode(loc)
+		if node == nil {
+			return nil, status.Errorf(codes.NotFound, "Location %x not found", loc)
+		}
+		if err := node.Verify(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	} else {
+		// Forward request to the correct server
+		var node *dbv2.Node
+		err := self.forwardLocation(ctx, loc, func(ctx context.Context, conn *grpc.ClientConn) error {
+			var err error
+			node, err = dbv2.NewDbServiceClient(conn).GetNode(ctx, &dbv2.GetNodeRequest{Location: loc})
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		n := storage.NewNodeV2(node)
+		if err := n.Verify(); err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type historyStream struct {
+	grpc.ServerStream
+	values []string
+}
+
+func (s *historyStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *historyStream) Send(node *dbv2.Node) error {
+	s.values = append(s.values, string(node.Key)+"="+string(node.Value))
+	return nil
+}
+
+func TestHistoryFollowsDep(t *testing.T) {
+	s := &ServerV2{server: newTestServer(t)}
+	set := func(key, value string, dep uint64) uint64 {
+		return storage.Set(store, storage.Node{Key: key, Value: []byte(value), Dep: dep}, 0)
+	}
+
+	a1 := set("a", "1", 0)
+	b1 := set("b", "1", a1)
+	a2 := set("a", "2", b1)
+	b2 := set("b", "2", a2)
+
+	for _, c := range []struct {
+		in   *dbv2.HistoryRequest
+		want []string
+	}{
+		{&dbv2.HistoryRequest{Location: b2}, []string{"b=2", "a=2", "b=1", "a=1"}},
+		{&dbv2.HistoryRequest{Location: b2, Key: []byte("a")}, []string{"a=2", "a=1"}},
+		{&dbv2.HistoryRequest{Location: b2, Key: []byte("a"), Limit: 1}, []string{"a=2"}},
+		{&dbv2.HistoryRequest{Location: b1, Limit: 3}, []string{"b=1", "a=1"}},
+	} {
+		stream := &historyStream{}
+		if err := s.History(c.in, stream); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(stream.values, c.want) {
+			t.Fatalf("History of %v is %v instead of %v", c.in, stream.values, c.want)
+		}
+	}
+
+	// Dep removed without relinking
+	orphan := set("a", "3", storage.NewLocation("a"))
+	err := s.History(&dbv2.HistoryRequest{Location: orphan}, &historyStream{})
+	if status.Code(err) != codes.DataLoss {
+		t.Fatalf("History with a missing Dep fails with error %v", err)
+	}
+}
+
+func TestHeadsOfConcurrentVersions(t *testing.T) {
+	s := &ServerV2{server: newTestServer(t)}
+	ctx := context.Background()
+	set := func(key, value string, dep uint64) uint64 {
+		loc := storage.Set(store, storage.Node{Key: key, Value: []byte(value), Dep: dep}, 0)
+		if dep != 0 {
+			store.AddChild(dep, loc)
+		}
+		return loc
+	}
+
+	a1 := set("a", "1", 0)
+	// Concurrent versions on top of a1
+	a2 := set("a", "2", a1)
+	a3 := set("a", "3", a1)
+	b1 := set("b", "1", a2)
+
+	for _, c := range []struct {
+		key  string
+		want []uint64
+	}{
+		{"a", []uint64{a2, a3}},
+		{"b", []uint64{b1}},
+		// Leaves of any key
+		{"", []uint64{b1, a3}},
+	} {
+		resp, err := s.Heads(ctx, &dbv2.HeadsRequest{Key: []byte(c.key), Location: a1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		heads := make(map[uint64]bool)
+		for _, loc := range resp.Locations {
+			heads[loc] = true
+		}
+		want := make(map[uint64]bool)
+		for _, loc := range c.want {
+			want[loc] = true
+		}
+		if len(resp.Locations) != len(c.want) || !reflect.DeepEqual(heads, want) {
+			t.Fatalf("Heads of %q are %x instead of %x", c.key, resp.Locations, c.want)
+		}
+	}
+}
+
+func TestAddNodeVerifiesChecksum(t *testing.T) {
+	s := &ServerV2{server: newTestServer(t)}
+
+	node := storage.Node{Key: "a", Value: []byte("1"), Location: storage.NewLocation("a")}
+	node.Checksum = node.ComputeChecksum()
+	sent := node.ProtoV2()
+	sent.Value = []byte("2")
+	_, err := s.AddNode(context.Background(), &dbv2.AddNodeRequest{Node: sent})
+	if status.Code(err) != codes.DataLoss {
+		t.Fatalf("Corrupted node is added with error %v", err)
+	}
+	if store.GetNode(node.Location) != nil {
+		t.Fatalf("Corrupted node is stored")
+	}
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/indexing"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+)
+
+// A single server owning all keys with an in-memory store
+func newTestServer(t *testing.T) *Server {
+	var err error
+	store, err = storage.NewEngine(storage.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		store.Close()
+	})
+
+	s := &Server{
+		Self:    "localhost:9000",
+		Initial: "localhost:9000",
+		Servers: []string{"localhost:9000"},
+	}
+	s.mergeFunction = make(map[uint64]string)
+	indexingService.Init(indexing.NewRangePartitioner(s.Initial))
+	return s
+}
+
+func TestApplyMergeKeepsExistingChildren(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	parent := storage.Set(store, storage.Node{Key: "votes", Value: []byte("1")}, 0)
+	tombstone := storage.Delete(store, "votes", parent)
+	update := storage.Set(store, storage.Node{Key: "votes", Value: []byte("2"), Dep: parent}, 0)
+	store.AddChild(parent, tombstone)
+	node := store.AddChild(parent, update)
+
+	// Keep the tombstone and replace the update
+	merged := storage.CreateNode("votes", "3", parent)
+	s.applyMerge(ctx, node.Proto(), []*db.Node{store.GetNode(tombstone).Proto(), merged})
+
+	if n := store.GetNode(tombstone); n == nil || !n.Deleted {
+		t.Fatalf("Tombstone returned by the merge function is removed")
+	}
+	if store.GetNode(update) != nil {
+		t.Fatalf("Child not returned by the merge function is not removed")
+	}
+	if n := store.GetNode(merged.Location); n == nil || string(n.Value) != "3" {
+		t.Fatalf("Node created by the merge function is not added")
+	}
+	children := store.GetNode(parent).Children
+	if want := []uint64{tombstone, merged.Location}; !reflect.DeepEqual(children, want) {
+		t.Fatalf("Children are %x instead of %x", children, want)
+	}
+}
+
+// Run with -race
+func TestConcurrentSetGetSplit(t *testing.T) {
+	s := newTestServer(t)
+	s.GC = storage.RetentionPolicy{KeepVersions: 1}
+	ctx := context.Background()
+
+	const writers = 8
+	const versions = 50
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", w)
+			loc := uint64(0)
+			for i := 0; i < versions; i++ {
+				resp, err := s.Set(ctx, &db.SetRequest{Key: key, Value: strconv.Itoa(i), Dep: loc})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				loc = resp.Location
+				if i%10 == 0 {
+					s.SetMergeFunction(ctx, &db.SetMergeFunctionRequest{Location: loc, Name: "merge"})
+				}
+				got, err := s.Get(ctx, &db.GetRequest{Key: key, Location: loc})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if got.Value != strconv.Itoa(i) {
+					t.Errorf("Got %s instead of %d for %s", got.Value, i, key)
+					return
+				}
+			}
+		}(w)
+	}
+
+	// Tables of splits keeping all keys on this server and GC
+	var background sync.WaitGroup
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		for {
+			current := indexingService.Table()
+			table, err := encodeTable(&indexing.Table{Partitioner: current.Clone(), Epoch: current.Epoch + 1})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := s.Split(ctx, &db.SplitRequest{Table: table}); err != nil {
+				t.Error(err)
+				return
+			}
+			s.collect()
+
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	background.Wait()
+
+	if indexingService.Table().Epoch == 0 {
+		t.Fatalf("No table is used by splits")
+	}
+}
+
+func TestDistributeNodesMovesDependents(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	// Random parts 1, 1, 2 and 3
+	storage.SetRandomSource(storage.NewRepeatSource([]int64{1 << 31, 1 << 31, 2 << 31, 3 << 31}))
+	defer storage.SetRandomSource(rand.NewSource(time.Now().UnixNano()))
+
+	taken := storage.Set(store, storage.Node{Key: "key", Value: []byte("1")}, 0)
+	// Merge nodes on top of each other, the first one assigned the location of an existing node
+	first := storage.CreateNode("key", "2", 0)
+	second := storage.CreateNode("key", "3", first.Location)
+	s.distributeNodes(ctx, []*db.Node{first, second})
+
+	if first.Location == taken {
+		t.Fatalf("Node colliding at %x is not moved", taken)
+	}
+	if second.Dep != first.Location {
+		t.Fatalf("Dep of the second node is %x instead of %x", second.Dep, first.Location)
+	}
+	if n := store.GetNode(second.Location); n == nil || n.Dep != first.Location {
+		t.Fatalf("Second node is not added on top of the moved node")
+	}
+	if n := store.GetNode(taken); n == nil || string(n.Value) != "1" {
+		t.Fatalf("Existing node is overwritten")
+	}
+}
+
+func TestAddNodeV1KeepsBinaryValue(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+
+	loc := storage.Set(store, storage.Node{Key: "key", Value: []byte{0xff, 0xfe}}, 0)
+	// Sent again through the v1 API with a new child
+	node := store.GetNode(loc).Proto()
+	node.Children = []uint64{1}
+	if _, err := s.AddNode(ctx, &db.AddNodeRequest{Node: node}); err != nil {
+		t.Fatal(err)
+	}
+
+	n := store.GetNode(loc)
+	if string(n.Value) != "\xff\xfe" {
+		t.Fatalf("Binary value is replaced by %q", n.Value)
+	}
+	if !reflect.DeepEqual(n.Children, []uint64{1}) {
+		t.Fatalf("Children are %x instead of [1]", n.Children)
+	}
+}
+
+// Moving history written before timestamps were recorded keeps its commit time
+func TestAddNodeKeepsCreatedCommitTime(t *testing.T) {
+	s := newTestServer(t)
+
+	created := time.Now().Add(-time.Hour).UnixNano()
+	node := storage.Node{Key: "key", Value: []byte("1"), Created: created, Location: storage.NewLocation("key")}
+	if err := s.addNode(node); err != nil {
+		t.Fatal(err)
+	}
+	if commit := store.GetNode(node.Location).CommitTime(); storage.TimestampTime(commit).UnixNano() > created {
+		t.Fatalf("Node created at %d is committed at %d", created, storage.TimestampTime(commit).UnixNano())
+	}
+}
+
+func TestStatsReportsDedup(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	// The store replacing it is closed by the cleanup of the test server
+	store.Close()
+	var err error
+	store, err = storage.NewEngine(storage.Options{Dedup: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := strings.Repeat("v", 100)
+	for _, key := range []string{"a", "b"} {
+		if _, err := s.Set(ctx, &db.SetRequest{Key: key, Value: value}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := s.Stats(ctx, &db.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Nodes != 2 || stats.DedupBlobs != 1 || stats.DedupSaved != int64(len(value)) {
+		t.Fatalf("Stats are %v instead of 2 nodes sharing 1 value", stats)
+	}
+}
+
+func TestStatsReportsTierHits(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	store.Close()
+	var err error
+	store, err = storage.NewEngine(storage.Options{Dir: t.TempDir(), SpillAfter: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.Set(ctx, &db.SetRequest{Key: "a", Value: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.spill(store.(storage.Spiller), time.Now().Add(time.Second))
+	stats, err := s.Stats(ctx, &db.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Spilled == 0 {
+		t.Fatalf("Stats are %v without spilled nodes", stats)
+	}
+
+	if _, err := s.Get(ctx, &db.GetRequest{Key: "a", Location: resp.Location}); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = s.Stats(ctx, &db.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.DiskHits == 0 {
+		t.Fatalf("Stats are %v without reads from disk", stats)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/storage"
+)
+
+func (s *Server) spillLoop(spiller storage.Spiller) {
+	after := time.Second * time.Duration(s.Storage.SpillAfter)
+	ticker := time.NewTicker(after)
+	defer ticker.Stop()
+	for range ticker.C {
+		s.spill(spiller, time.Now().Add(-after))
+	}
+}
+
+// Move nodes not read since before to disk
+func (s *Server) spill(spiller storage.Spiller, before time.Time) {
+	spilled, err := spiller.Spill(before)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	// Debug
+	stats := spiller.TierStats()
+	fmt.Println("[Spill]")
+	fmt.Printf("Spilled: %d, On disk: %d\n", spilled, stats.Spilled)
+	if total := stats.MemoryHits + stats.DiskHits; total > 0 {
+		fmt.Printf("Hits: memory %d (%.1f%%), disk %d (%.1f%%)\n",
+			stats.MemoryHits, float64(stats.MemoryHits)*100/float64(total),
+			stats.DiskHits, float64(stats.DiskHits)*100/float64(total))
+	}
+	fmt.Printf("Nodes: %d, Bytes: %d\n", store.Count(), store.Bytes())
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"context"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/storage"
+)
+
+func (s *Server) Stats(ctx context.Context, in *db.Empty) (*db.StatsResponse, error) {
+	resp := &db.StatsResponse{
+		Nodes: int64(store.Count()),
+		Bytes: store.Bytes(),
+	}
+	if deduper, ok := store.(storage.Deduper); ok {
+		blobs, saved := deduper.DedupStats()
+		resp.DedupBlobs = int64(blobs)
+		resp.DedupSaved = saved
+	}
+	if spiller, ok := store.(storage.Spiller); ok {
+		tiers := spiller.TierStats()
+		resp.MemoryHits = tiers.MemoryHits
+		resp.DiskHits = tiers.DiskHits
+		resp.Spilled = int64(tiers.Spilled)
+	}
+	return resp, nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+
+	"github.com/DCsunset/openwhisk-grpc/indexing"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Checksum of everything but children, which change when versions are added.
+// Never 0 so that 0 means unknown.
+func (n *Node) ComputeChecksum() uint32 {
+	var buf [8]byte
+	sum := uint32(0)
+	writeUint := func(v uint64) {
+		binary.LittleEndian.PutUint64(buf[:], v)
+		sum = crc32.Update(sum, castagnoli, buf[:])
+	}
+	writeBytes := func(data []byte) {
+		writeUint(uint64(len(data)))
+		sum = crc32.Update(sum, castagnoli, data)
+	}
+	writeBool := func(v bool) {
+		if v {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	}
+
+	writeUint(n.Location)
+	writeUint(n.Dep)
+	writeBytes([]byte(n.Key))
+	writeBytes(n.Value)
+	writeUint(uint64(n.Created))
+	writeBool(n.Deleted)
+	writeUint(uint64(n.Expires))
+	writeBytes([]byte(n.ContentType))
+	writeUint(n.Next)
+	writeBool(n.Chunk)
+	writeUint(uint64(n.Length))
+	writeBool(n.Compressed)
+	// Keep checksums of versions without timestamp
+	if n.Timestamp != 0 {
+		writeUint(n.Timestamp)
+	}
+	if sum == 0 {
+		sum = 1
+	}
+	return sum
+}
+
+// Return a DataLoss error if the node does not match its checksum
+func (n *Node) Verify() error {
+	if n.Checksum != 0 && n.Checksum != n.ComputeChecksum() {
+		return status.Errorf(codes.DataLoss, "Checksum mismatch of node %x", n.Location)
+	}
+	return nil
+}
+
+type ScrubReport struct {
+	Checked     int
+	Corrupted   []uint64
+	Orphaned    []uint64 // Dep is missing
+	Dangling    []uint64 // Some children or chunks are missing
+	BadMappings []uint64
+}
+
+// Engines that can check their own index
+type mappingChecker interface {
+	checkMappings() []uint64
+}
+
+// Check nodes in the ranges.
+// exists is used to find nodes outside the ranges (possibly remote).
+func Scrub(e Engine, ranges []indexing.Mapping, exists func(loc uint64) bool) *ScrubReport {
+	report := &ScrubReport{}
+	var nodes []*Node
+	local := make(map[uint64]bool)
+	for _, r := range ranges {
+		e.Range(r.Left, r.Right, func(node *Node) bool {
+			n := *node
+			nodes = append(nodes, &n)
+			local[n.Location] = true
+			return true
+		})
+	}
+
+	// Every server has a root
+	cache := map[uint64]bool{0: true}
+	found := func(loc uint64) bool {
+		// Nodes of key hashes in the ranges might be on other servers sharing them
+		if local[loc] {
+			return true
+		}
+		ok, cached := cache[loc]
+		if !cached {
+			ok = exists(loc)
+			cache[loc] = ok
+		}
+		return ok
+	}
+
+	for _, node := range nodes {
+		report.Checked += 1
+		if node.Verify() != nil {
+			report.Corrupted = append(report.Corrupted, node.Location)
+		}
+		if !node.Chunk && !found(node.Dep) {
+			report.Orphaned = append(report.Orphaned, node.Location)
+		}
+		dangling := node.Next != 0 && !found(node.Next)
+		for _, child := range node.Children {
+			if !found(child) {
+				dangling = true
+			}
+		}
+		if dangling {
+			report.Dangling = append(report.Dangling, node.Location)
+		}
+	}
+
+	if checker, ok := e.(mappingChecker); ok {
+		report.BadMappings = checker.checkMappings()
+	}
+	return report
+}
+
+// Locations mapped to a slot holding another node or a removed one
+func (s *Store) checkMappings() []uint64 {
+	s.lockAll()
+	defer s.unlockAll()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	free := make(map[int]bool, len(s.free))
+	for _, memLoc := range s.free {
+		free[memLoc] = true
+	}
+	var bad []uint64
+	for loc, memLoc := range s.MemLocation {
+		if memLoc < 0 || memLoc >= len(s.Nodes) || free[memLoc] || s.Nodes[memLoc].Location != loc {
+			bad = append(bad, loc)
+		}
+	}
+	return bad
+}
@@ -0,0 +1,167 @@
+package storage
+
+import (
+	"fmt"
+)
+
+const DefaultChunkSize = 256 * 1024
+
+// Store a large value as chunks linked by Next.
+// Chunks have the same key hash as the version so they stay on the same server.
+type ChunkWriter struct {
+	e       Engine
+	key     string
+	size    int
+	buf     []byte
+	pending *Node // Last chunk waiting for the location of next chunk
+	prev    *Node // Chunk written before pending
+	first   uint64
+	length  int64
+	written []uint64
+}
+
+func NewChunkWriter(e Engine, key string, size int) *ChunkWriter {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	return &ChunkWriter{
+		e:    e,
+		key:  key,
+		size: size,
+	}
+}
+
+func (w *ChunkWriter) Write(data []byte) (int, error) {
+	w.buf = append(w.buf, data...)
+	w.length += int64(len(data))
+	for len(w.buf) >= w.size {
+		w.push(w.buf[:w.size])
+		w.buf = w.buf[w.size:]
+	}
+	return len(data), nil
+}
+
+func (w *ChunkWriter) push(data []byte) {
+	chunk := &Node{
+		Location: NewLocation(w.key),
+		Key:      w.key,
+		Value:    append([]byte(nil), data...),
+		Chunk:    true,
+	}
+	if w.pending == nil {
+		w.first = chunk.Location
+	} else {
+		w.pending.Next = chunk.Location
+		w.flush()
+	}
+	w.pending = chunk
+}
+
+func (w *ChunkWriter) flush() {
+	for !w.e.PutNewNode(*w.pending) {
+		// Move the chunk and fix the link to it
+		w.pending.Location = NewLocation(w.key)
+		if w.prev == nil {
+			w.first = w.pending.Location
+		} else {
+			w.prev.Next = w.pending.Location
+			w.e.PutNode(*w.prev)
+		}
+	}
+	w.written = append(w.written, w.pending.Location)
+	w.prev = w.pending
+	w.pending = nil
+}
+
+// Return the first chunk (0 if empty) and size of the value
+func (w *ChunkWriter) Close() (uint64, int64) {
+	if len(w.buf) > 0 {
+		w.push(w.buf)
+		w.buf = nil
+	}
+	if w.pending != nil {
+		w.flush()
+	}
+	return w.first, w.length
+}
+
+// Remove chunks already written
+func (w *ChunkWriter) Abort() {
+	for _, loc := range w.written {
+		w.e.RemoveNode(loc)
+	}
+	w.written = nil
+	w.pending = nil
+	w.prev = nil
+}
+
+// Call fn with each part of the value in order after verifying it
+func ReadValue(e Engine, node *Node, fn func(data []byte) error) error {
+	if !node.Chunked() {
+		if err := node.Verify(); err != nil {
+			return err
+		}
+		if err := node.Decompress(); err != nil {
+			return err
+		}
+		return fn(node.Value)
+	}
+	for loc := node.Next; loc != 0; {
+		chunk := e.GetNode(loc)
+		if chunk == nil || !chunk.Chunk {
+			return fmt.Errorf("Chunk %x of %x not found", loc, node.Location)
+		}
+		if err := chunk.Verify(); err != nil {
+			return err
+		}
+		if err := chunk.Decompress(); err != nil {
+			return err
+		}
+		if err := fn(chunk.Value); err != nil {
+			return err
+		}
+		loc = chunk.Next
+	}
+	return nil
+}
+
+// Reassemble the whole value
+func Value(e Engine, node *Node) ([]byte, error) {
+	if !node.Chunked() {
+		if err := node.Verify(); err != nil {
+			return nil, err
+		}
+		err := node.Decompress()
+		return node.Value, err
+	}
+	value := make([]byte, 0, node.Length)
+	err := ReadValue(e, node, func(data []byte) error {
+		value = append(value, data...)
+		return nil
+	})
+	return value, err
+}
+
+// Remove a node and its chunks
+func Remove(e Engine, loc uint64) {
+	node := e.GetNode(loc)
+	if node == nil {
+		return
+	}
+	RemoveChunks(e, node)
+	e.RemoveNode(loc)
+}
+
+func RemoveChunks(e Engine, node *Node) {
+	if !node.Chunked() {
+		return
+	}
+	for loc := node.Next; loc != 0; {
+		chunk := e.GetNode(loc)
+		if chunk == nil {
+			return
+		}
+		e.RemoveNode(loc)
+		loc = chunk.Next
+	}
+}
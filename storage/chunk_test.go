@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValueVerifiesCompressedChunks(t *testing.T) {
+	e, err := NewEngine(Options{CompressThreshold: 64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	w := NewChunkWriter(e, "key", 1024)
+	w.Write(bytes.Repeat([]byte("t"), 4096))
+	first, length := w.Close()
+	node := &Node{Key: "key", Next: first, Length: length}
+
+	chunk := e.GetNode(first)
+	if !chunk.Compressed {
+		t.Fatalf("Chunk is not compressed")
+	}
+	// Flip a bit of the stored compressed chunk
+	s := e.(*Store)
+	s.Nodes[s.MemLocation[first]].Value[0] ^= 1
+
+	if _, err := Value(e, node); status.Code(err) != codes.DataLoss {
+		t.Fatalf("Corrupted chunk is read with error %v", err)
+	}
+}
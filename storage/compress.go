@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"bytes"
+	"compress/flate"
+	"io/ioutil"
+	"log"
+)
+
+// Compress value of node in place if it is large enough
+func compressNode(node *Node, threshold int) {
+	if threshold <= 0 || node.Compressed || len(node.Value) < threshold {
+		return
+	}
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	writer.Write(node.Value)
+	writer.Close()
+	// Not worth it
+	if buf.Len() >= len(node.Value) {
+		return
+	}
+	if !node.Chunked() {
+		node.Length = int64(len(node.Value))
+	}
+	node.Value = buf.Bytes()
+	node.Compressed = true
+}
+
+func decompress(data []byte) ([]byte, error) {
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// Replace value with the uncompressed one.
+// The checksum is of the compressed value, so nodes must be verified first.
+func (n *Node) Decompress() error {
+	if !n.Compressed {
+		return nil
+	}
+	value, err := decompress(n.Value)
+	if err != nil {
+		return err
+	}
+	n.Value = value
+	n.Compressed = false
+	// Unknown instead of recomputed from a value that has not been verified
+	n.Checksum = 0
+	return nil
+}
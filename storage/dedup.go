@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"sync/atomic"
+)
+
+// Smaller values are not worth the hash and the table entry
+const minDedupSize = 64
+
+// A value shared by all nodes with the same content
+type blob struct {
+	hash  [sha256.Size]byte
+	value []byte
+	refs  int
+}
+
+// Point the value of node to the blob with the same content
+func (s *Store) acquire(node *Node) {
+	node.blob = nil
+	if !s.dedup || len(node.Value) < minDedupSize {
+		return
+	}
+	hash := sha256.Sum256(node.Value)
+	b, ok := s.blobs[hash]
+	if !ok {
+		b = &blob{hash: hash, value: node.Value}
+		s.blobs[hash] = b
+		atomic.AddInt64(&s.bytes, int64(len(b.value)))
+	}
+	b.refs += 1
+	node.Value = b.value
+	node.blob = b
+}
+
+// Drop the reference of node and free the blob if unused
+func (s *Store) release(node *Node) {
+	b := node.blob
+	if b == nil {
+		return
+	}
+	node.blob = nil
+	b.refs -= 1
+	if b.refs == 0 {
+		delete(s.blobs, b.hash)
+		atomic.AddInt64(&s.bytes, -int64(len(b.value)))
+	}
+}
+
+// Bytes used by node itself since a shared value is counted by its blob
+func (s *Store) usage(node *Node) int64 {
+	if node.blob != nil {
+		return node.Bytes() - int64(len(node.Value))
+	}
+	return node.Bytes()
+}
+
+func (s *Store) DedupStats() (blobs int, saved int64) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, b := range s.blobs {
+		saved += int64(b.refs-1) * int64(len(b.value))
+	}
+	return len(s.blobs), saved
+}
@@ -0,0 +1,289 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const diskFile = "nodes.db"
+
+var (
+	nodesBucket = []byte("nodes")
+	metaBucket  = []byte("meta")
+	mergeKey    = []byte("mergeFunctions")
+	globalKey   = []byte("globalMergeFunction")
+)
+
+// On-disk store based on B+tree.
+// Nodes are sorted by location so that a hash range is a range of keys.
+type DiskStore struct {
+	db    *bolt.DB
+	dir   string
+	lock  sync.Mutex
+	size  int
+	bytes int64
+
+	compressThreshold int
+}
+
+func locationKey(loc uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, loc)
+	return key
+}
+
+func encodeNode(node *Node) []byte {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(node); err != nil {
+		log.Fatalln(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeNode(data []byte) *Node {
+	var node Node
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&node); err != nil {
+		log.Fatalln(err)
+	}
+	return &node
+}
+
+func (s *DiskStore) Init(opts Options) error {
+	if len(opts.Dir) == 0 {
+		return fmt.Errorf("Disk engine requires a data directory")
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return err
+	}
+	database, err := bolt.Open(filepath.Join(opts.Dir, diskFile), 0644, nil)
+	if err != nil {
+		return err
+	}
+	database.NoSync = opts.Sync == SyncNone
+	s.db = database
+	s.dir = opts.Dir
+	s.compressThreshold = opts.CompressThreshold
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(metaBucket); err != nil {
+			return err
+		}
+		bucket, err := tx.CreateBucketIfNotExists(nodesBucket)
+		if err != nil {
+			return err
+		}
+		root := locationKey(0)
+		if bucket.Get(root) == nil {
+			node := rootNode()
+			if err := bucket.Put(root, encodeNode(&node)); err != nil {
+				return err
+			}
+		}
+		s.size = -1
+		s.bytes = 0
+		cursor := bucket.Cursor()
+		for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+			s.size += 1
+			s.bytes += decodeNode(v).Bytes()
+		}
+		return nil
+	})
+}
+
+func (s *DiskStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *DiskStore) update(fn func(bucket *bolt.Bucket) error) {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		return fn(tx.Bucket(nodesBucket))
+	})
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func (s *DiskStore) PutNode(node Node) {
+	s.put(node, true)
+}
+
+func (s *DiskStore) PutNewNode(node Node) bool {
+	return s.put(node, false)
+}
+
+// Write a node and return whether it is written
+func (s *DiskStore) put(node Node, replace bool) bool {
+	compressNode(&node, s.compressThreshold)
+	node.Checksum = node.ComputeChecksum()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	written := true
+	key := locationKey(node.Location)
+	s.update(func(bucket *bolt.Bucket) error {
+		if data := bucket.Get(key); data == nil {
+			s.size += 1
+		} else if !replace {
+			written = false
+			return nil
+		} else {
+			s.bytes -= decodeNode(data).Bytes()
+		}
+		s.bytes += node.Bytes()
+		return bucket.Put(key, encodeNode(&node))
+	})
+	return written
+}
+
+func (s *DiskStore) GetNode(loc uint64) *Node {
+	var node *Node
+	s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(nodesBucket).Get(locationKey(loc))
+		if data != nil {
+			node = decodeNode(data)
+		}
+		return nil
+	})
+	return node
+}
+
+// Read-modify-write a node
+func (s *DiskStore) modify(loc uint64, fn func(node *Node)) *Node {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var node *Node
+	key := locationKey(loc)
+	s.update(func(bucket *bolt.Bucket) error {
+		data := bucket.Get(key)
+		if data == nil {
+			return nil
+		}
+		node = decodeNode(data)
+		s.bytes -= node.Bytes()
+		fn(node)
+		s.bytes += node.Bytes()
+		return bucket.Put(key, encodeNode(node))
+	})
+	return node
+}
+
+func (s *DiskStore) AddChild(loc uint64, child uint64) *Node {
+	return s.modify(loc, func(node *Node) {
+		node.Children = append(node.Children, child)
+	})
+}
+
+func (s *DiskStore) SetChildren(loc uint64, children []uint64) {
+	s.modify(loc, func(node *Node) {
+		node.Children = children
+	})
+}
+
+func (s *DiskStore) RemoveNode(loc uint64) {
+	if loc == 0 {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key := locationKey(loc)
+	s.update(func(bucket *bolt.Bucket) error {
+		data := bucket.Get(key)
+		if data == nil {
+			return nil
+		}
+		s.size -= 1
+		s.bytes -= decodeNode(data).Bytes()
+		return bucket.Delete(key)
+	})
+}
+
+func (s *DiskStore) Range(left, right uint32, fn func(node *Node) bool) {
+	s.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(nodesBucket).Cursor()
+		end := locationKey(uint64(right)<<32 | 0xffffffff)
+		for k, v := cursor.Seek(locationKey(uint64(left) << 32)); k != nil && bytes.Compare(k, end) <= 0; k, v = cursor.Next() {
+			node := decodeNode(v)
+			if node.Location != 0 && !fn(node) {
+				break
+			}
+		}
+		return nil
+	})
+}
+
+func (s *DiskStore) Count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.size
+}
+
+func (s *DiskStore) Bytes() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.bytes
+}
+
+// Every write is already in the database, which is all that is loaded on restart
+func (s *DiskStore) Snapshot() error {
+	return status.Errorf(codes.Unimplemented, "Disk engine does not take snapshots")
+}
+
+// Every write is already durable
+func (s *DiskStore) NeedSnapshot() bool {
+	return false
+}
+
+func decodeMergeFunctions(meta *bolt.Bucket) map[uint64]string {
+	functions := make(map[uint64]string)
+	if data := meta.Get(mergeKey); data != nil {
+		gob.NewDecoder(bytes.NewReader(data)).Decode(&functions)
+	}
+	return functions
+}
+
+func (s *DiskStore) SetMergeFunction(loc uint64, name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		functions := decodeMergeFunctions(meta)
+		if len(name) == 0 {
+			delete(functions, loc)
+		} else {
+			functions[loc] = name
+		}
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(functions); err != nil {
+			return err
+		}
+		return meta.Put(mergeKey, buf.Bytes())
+	})
+}
+
+func (s *DiskStore) SetGlobalMergeFunction(name string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put(globalKey, []byte(name))
+	})
+}
+
+func (s *DiskStore) MergeFunctions() (map[uint64]string, string) {
+	var functions map[uint64]string
+	var global string
+	s.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		functions = decodeMergeFunctions(meta)
+		global = string(meta.Get(globalKey))
+		return nil
+	})
+	return functions, global
+}
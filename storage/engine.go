@@ -0,0 +1,264 @@
+package storage
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/latency"
+	"github.com/DCsunset/openwhisk-grpc/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Storage engines
+const (
+	EngineMemory = "memory" // slice-based in-memory store
+	EngineDisk   = "disk"   // on-disk B+tree
+)
+
+// Simulated latency of Get, Set and Delete (none if nil)
+var Latency *latency.Injector
+
+// Engine stores the nodes of a server
+type Engine interface {
+	Init(opts Options) error
+	Close() error
+
+	// Add a node or replace the node at the same location
+	PutNode(node Node)
+	// Add a node unless its location is taken and return whether it is added
+	PutNewNode(node Node) bool
+	// Return nil if location not found
+	GetNode(loc uint64) *Node
+	AddChild(loc uint64, child uint64) *Node
+	SetChildren(loc uint64, children []uint64)
+	RemoveNode(loc uint64)
+	// Iterate nodes (except root) whose key hash is in [left, right]
+	// until fn returns false (fn must not modify the engine)
+	Range(left, right uint32, fn func(node *Node) bool)
+	// Number of nodes except root (including chunks)
+	Count() int
+	// Estimated bytes used by all nodes
+	Bytes() int64
+}
+
+// Engines that can persist a point-in-time copy of all data
+type Snapshotter interface {
+	Snapshot() error
+	// Whether a snapshot should be taken automatically
+	NeedSnapshot() bool
+	// Persist the merge function of loc (removed if name is empty)
+	SetMergeFunction(loc uint64, name string) error
+	SetGlobalMergeFunction(name string) error
+	// Merge functions of locations and the global one restored on start
+	MergeFunctions() (map[uint64]string, string)
+}
+
+// Engines that can share identical values
+type Deduper interface {
+	// Number of distinct values stored and bytes saved by sharing them
+	DedupStats() (blobs int, saved int64)
+}
+
+// Engines that can move cold nodes out of memory
+type Spiller interface {
+	// Move nodes not read since before out of memory
+	Spill(before time.Time) (int, error)
+	TierStats() TierStats
+}
+
+func NewEngine(opts Options) (Engine, error) {
+	var e Engine
+	switch opts.Engine {
+	case "", EngineMemory:
+		e = &Store{}
+	case EngineDisk:
+		e = &DiskStore{}
+	default:
+		return nil, fmt.Errorf("Unknown storage engine %s", opts.Engine)
+	}
+	if err := e.Init(opts); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func rootNode() Node {
+	return Node{
+		Dep:      math.MaxUint64,
+		Location: 0,
+		Key:      "",
+	}
+}
+
+// Read a node which might be on another server
+type Lookup func(loc uint64) (*Node, error)
+
+// Find the latest version of key visible from loc
+// committed at or before timestamp asOf (0 for now)
+func Get(lookup Lookup, key string, loc uint64, asOf uint64) (*Node, error) {
+	Latency.Get()
+
+	node, err := lookup(loc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find till root
+	now := time.Now()
+	if asOf != 0 {
+		now = TimestampTime(asOf)
+	}
+	for {
+		if err := node.Verify(); err != nil {
+			return nil, err
+		}
+		// Versions along Dep links are committed in order
+		if node.Key == key && (asOf == 0 || node.CommitTime() <= asOf) {
+			if node.Deleted || node.Expired(now) {
+				break
+			}
+			return node, nil
+		}
+		if node.Dep == math.MaxUint64 {
+			break
+		}
+		child := node.Location
+		node, err = lookup(node.Dep)
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.DataLoss, "Dep of %x not found", child)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nil, status.Errorf(codes.NotFound, "Key %s not found", key)
+}
+
+// Read the version at loc
+func GetVersion(lookup Lookup, loc uint64) (*Node, error) {
+	Latency.Get()
+
+	node, err := lookup(loc)
+	if err != nil {
+		return nil, err
+	}
+	if err := node.Verify(); err != nil {
+		return nil, err
+	}
+	if loc == 0 || node.Chunk {
+		return nil, status.Errorf(codes.NotFound, "No version at location %x", loc)
+	}
+	if node.Deleted || node.Expired(time.Now()) {
+		return nil, status.Errorf(codes.NotFound, "Version at location %x is deleted", loc)
+	}
+	return node, nil
+}
+
+// Create a new version with Key, Value, Dep and ContentType of node
+// which expires after ttl (0 to never expire)
+func Set(e Engine, node Node, ttl time.Duration) uint64 {
+	Latency.Set()
+
+	if ttl > 0 {
+		node.Expires = time.Now().Add(ttl).UnixNano()
+	}
+	return put(e, node)
+}
+
+// Create a tombstone of key
+func Delete(e Engine, key string, dep uint64) uint64 {
+	Latency.Delete()
+
+	return put(e, Node{
+		Dep:     dep,
+		Key:     key,
+		Deleted: true,
+	})
+}
+
+// Seeded per process so that servers and merge actions do not generate the same locations
+var random = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(seed()))}
+
+// Generate the random part of new locations from src, such as one forcing collisions in tests
+func SetRandomSource(src rand.Source) {
+	random.Lock()
+	defer random.Unlock()
+	random.Rand = rand.New(src)
+}
+
+// Source of the same values in turn, which forces collisions in tests
+type RepeatSource struct {
+	values []int64
+	next   int
+}
+
+func NewRepeatSource(values []int64) *RepeatSource {
+	return &RepeatSource{values: values}
+}
+
+func (s *RepeatSource) Int63() int64 {
+	v := s.values[s.next%len(s.values)]
+	s.next += 1
+	return v
+}
+
+func (s *RepeatSource) Seed(seed int64) {}
+
+func seed() int64 {
+	var buf [8]byte
+	if _, err := crand.Read(buf[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(buf[:]))
+}
+
+// Hash of key placing its nodes (set to that of the partitioner)
+var KeyHash = func(key string) uint32 {
+	return utils.Hash2Uint(utils.Hash([]byte(key)))
+}
+
+// Use random number + key hash.
+// The location might be taken so nodes must be added by PutNewNode.
+func NewLocation(key string) uint64 {
+	random.Lock()
+	n := random.Uint32()
+	random.Unlock()
+	return uint64(n) + (uint64(KeyHash(key)) << 32)
+}
+
+// Add node at a new location
+func put(e Engine, node Node) uint64 {
+	node.Created = time.Now().UnixNano()
+	node.Timestamp = Clock.Now()
+	for {
+		// Retry on collision
+		node.Location = NewLocation(node.Key)
+		if e.PutNewNode(node) {
+			return node.Location
+		}
+	}
+}
+
+// Create a node for merge functions.
+// The location only identifies it among the nodes returned by a merge function,
+// as the server moves it to a location of the key hash in use.
+func CreateNode(key, value string, dep uint64) *db.Node {
+	return &db.Node{
+		Location: NewLocation(key),
+		Dep:      dep,
+		Key:      key,
+		Value:    value,
+		Children: nil,
+		Created:  time.Now().UnixNano(),
+	}
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RetentionPolicy struct {
+	// Interval in seconds between collections (0 to disable GC)
+	Interval int `json:"interval"`
+	// Number of latest versions to keep for each key
+	KeepVersions int `json:"keepVersions"`
+	// Keep versions newer than this many seconds
+	KeepFor int `json:"keepFor"`
+}
+
+// Find nodes in [left, right] that can be removed.
+// A node is retained if it is a head (no children), among the latest versions of its key,
+// new enough, or pinned.
+// Other nodes are removed only if no retained node reads them,
+// i.e. every path from them to a retained descendant passes a newer version of the same key,
+// so Get on retained locations returns the same result after they are collapsed.
+// lookup is used to find (possibly remote) descendants,
+// and a node is retained if any of them cannot be read for reasons other than NotFound.
+func Collect(e Engine, left, right uint32, policy RetentionPolicy, pinned func(loc uint64) bool, lookup Lookup) []*Node {
+	versions := make(map[string][]*Node)
+	e.Range(left, right, func(node *Node) bool {
+		if node.Chunk {
+			return true
+		}
+		n := *node
+		versions[n.Key] = append(versions[n.Key], &n)
+		return true
+	})
+
+	deadline := time.Now().Add(-time.Second * time.Duration(policy.KeepFor)).UnixNano()
+	var candidates []*Node
+	for _, nodes := range versions {
+		// Latest first
+		sort.Slice(nodes, func(i, j int) bool {
+			return nodes[i].CommitTime() > nodes[j].CommitTime()
+		})
+		for i, node := range nodes {
+			if i >= policy.KeepVersions && node.Created <= deadline && len(node.Children) > 0 && !pinned(node.Location) {
+				candidates = append(candidates, node)
+			}
+		}
+	}
+
+	removable := make(map[uint64]bool)
+	for _, node := range candidates {
+		removable[node.Location] = true
+	}
+
+	type result struct {
+		node *Node
+		err  error
+	}
+	cache := make(map[uint64]result)
+	get := func(loc uint64) (*Node, error) {
+		r, ok := cache[loc]
+		if !ok {
+			r.node, r.err = lookup(loc)
+			cache[loc] = r
+		}
+		return r.node, r.err
+	}
+
+	// Whether a retained node can read key through node
+	var visible func(node *Node, key string, visited map[uint64]bool) bool
+	visible = func(node *Node, key string, visited map[uint64]bool) bool {
+		for _, loc := range node.Children {
+			if visited[loc] {
+				continue
+			}
+			visited[loc] = true
+			child, err := get(loc)
+			if status.Code(err) == codes.NotFound {
+				continue
+			}
+			if err != nil {
+				// Unreadable children might read key
+				return true
+			}
+			if child.Key == key {
+				continue
+			}
+			// Remote nodes are always treated as retained
+			if !removable[loc] {
+				return true
+			}
+			if visible(child, key, visited) {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Nodes that cannot be removed are retained, which might affect their ancestors
+	for changed := true; changed; {
+		changed = false
+		for _, node := range candidates {
+			if removable[node.Location] && visible(node, node.Key, make(map[uint64]bool)) {
+				delete(removable, node.Location)
+				changed = true
+			}
+		}
+	}
+
+	var results []*Node
+	for _, node := range candidates {
+		if removable[node.Location] {
+			results = append(results, node)
+		}
+	}
+	return results
+}
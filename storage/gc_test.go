@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCollectRetainsNodesWithUnreadableChildren(t *testing.T) {
+	e, err := NewEngine(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	parent := Set(e, Node{Key: "a", Value: []byte("1")}, 0)
+	// Child on another server
+	child := uint64(1)
+	e.AddChild(parent, child)
+
+	policy := RetentionPolicy{}
+	pinned := func(loc uint64) bool { return false }
+	collect := func(code codes.Code) []*Node {
+		return Collect(e, 0, ^uint32(0), policy, pinned, func(loc uint64) (*Node, error) {
+			return nil, status.Errorf(code, "Location %x not readable", loc)
+		})
+	}
+
+	if nodes := collect(codes.Unavailable); len(nodes) != 0 {
+		t.Fatalf("Collected %d nodes with unreadable children", len(nodes))
+	}
+	if nodes := collect(codes.NotFound); len(nodes) != 1 || nodes[0].Location != parent {
+		t.Fatalf("Node with missing children is not collected")
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"sync"
+	"time"
+)
+
+// Bits of the logical counter in a timestamp
+const logicalBits = 16
+
+// Hybrid logical clock.
+// A timestamp is Unix milliseconds followed by a logical counter,
+// so it is close to wall-clock time but never goes backwards
+// and is after every timestamp the server has seen.
+type HLC struct {
+	lock sync.Mutex
+	last uint64
+}
+
+// Clock of this process used for new versions
+var Clock = &HLC{}
+
+// Timestamp not earlier than anything happened at or before t
+func Timestamp(t time.Time) uint64 {
+	return uint64(t.UnixNano()/int64(time.Millisecond))<<logicalBits | (1<<logicalBits - 1)
+}
+
+// Wall-clock time of a timestamp
+func TimestampTime(ts uint64) time.Time {
+	return time.Unix(0, int64(ts>>logicalBits)*int64(time.Millisecond))
+}
+
+func (c *HLC) Now() uint64 {
+	physical := uint64(time.Now().UnixNano()/int64(time.Millisecond)) << logicalBits
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if physical > c.last {
+		c.last = physical
+	} else {
+		c.last += 1
+	}
+	return c.last
+}
+
+// Move the clock past a timestamp from another server
+func (c *HLC) Update(ts uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if ts > c.last {
+		c.last = ts
+	}
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClockOrdersTimestamps(t *testing.T) {
+	c := &HLC{}
+	first := c.Now()
+	if second := c.Now(); second <= first {
+		t.Fatalf("Timestamp %x is not after %x", second, first)
+	}
+
+	// Timestamp of a server with a clock ahead
+	remote := Timestamp(time.Now().Add(time.Hour))
+	c.Update(remote)
+	if next := c.Now(); next <= remote {
+		t.Fatalf("Timestamp %x is not after seen timestamp %x", next, remote)
+	}
+}
+
+func TestGetAsOf(t *testing.T) {
+	e, err := NewEngine(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	lookup := func(loc uint64) (*Node, error) {
+		node := e.GetNode(loc)
+		if node == nil {
+			return nil, status.Errorf(codes.NotFound, "Location %x not found", loc)
+		}
+		return node, nil
+	}
+
+	// Version written before timestamps were recorded
+	created := time.Now().Add(-time.Hour)
+	legacy := Node{Key: "a", Value: []byte("0"), Created: created.UnixNano(), Location: NewLocation("a")}
+	e.PutNewNode(legacy)
+	first := Set(e, Node{Key: "a", Value: []byte("1"), Dep: legacy.Location}, 0)
+	time.Sleep(2 * time.Millisecond)
+	between := time.Now()
+	time.Sleep(2 * time.Millisecond)
+	second := Set(e, Node{Key: "a", Value: []byte("2"), Dep: first}, 0)
+
+	for asOf, value := range map[uint64]string{
+		0:                                   "2",
+		Timestamp(between):                  "1",
+		Timestamp(created.Add(time.Minute)): "0",
+	} {
+		node, err := Get(lookup, "a", second, asOf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(node.Value) != value {
+			t.Fatalf("Version %s is read as of %x instead of %s", node.Value, asOf, value)
+		}
+	}
+	if _, err := Get(lookup, "a", second, Timestamp(created.Add(-time.Minute))); status.Code(err) != codes.NotFound {
+		t.Fatalf("Version before the first is read with error %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/DCsunset/openwhisk-grpc/db"
+	"github.com/DCsunset/openwhisk-grpc/dbv2"
+)
+
+type Node struct {
+	Location uint64 // The location of the key
+	Dep      uint64
+	Children []uint64
+	Key      string
+	Value    []byte
+	Created  int64 // Unix nanoseconds
+	Deleted  bool  // Tombstone
+	Expires  int64 // Unix nanoseconds (0 to never expire)
+	// Commit time by hybrid logical clock (0 for versions before it was added)
+	Timestamp uint64
+
+	ContentType string
+	// First chunk of a chunked value, or next chunk of a chunk (0 for none)
+	Next uint64
+	// Part of a chunked value instead of a version
+	Chunk bool
+	// Total size of a chunked value
+	Length int64
+	// Value is compressed with flate
+	Compressed bool
+	// CRC-32C of the contents except children (0 if unknown)
+	Checksum uint32
+
+	// Shared value in memory store (not persisted)
+	blob *blob
+	// Last time the node was read in memory store (Unix nanoseconds)
+	read int64
+}
+
+func NewNode(node *db.Node) Node {
+	return Node{
+		Location:  node.Location,
+		Dep:       node.Dep,
+		Key:       node.Key,
+		Value:     []byte(node.Value),
+		Children:  node.Children,
+		Created:   node.Created,
+		Deleted:   node.Deleted,
+		Expires:   node.Expires,
+		Timestamp: node.Timestamp,
+	}
+}
+
+func NewNodeV2(node *dbv2.Node) Node {
+	n := Node{
+		Location:   node.Location,
+		Dep:        node.Dep,
+		Key:        string(node.Key),
+		Value:      node.Value,
+		Children:   node.Children,
+		Deleted:    node.Deleted,
+		Next:       node.Next,
+		Chunk:      node.Chunk,
+		Compressed: node.Compressed,
+		Checksum:   node.Checksum,
+	}
+	if node.Metadata != nil {
+		n.Created = node.Metadata.Created
+		n.Expires = node.Metadata.Expires
+		n.ContentType = node.Metadata.ContentType
+		n.Timestamp = node.Metadata.Timestamp
+		if n.Chunked() || n.Compressed {
+			n.Length = int64(node.Metadata.Size)
+		}
+	}
+	return n
+}
+
+// Strings in v1 API must be valid UTF-8 so binary values are not preserved
+func (n *Node) Proto() *db.Node {
+	value := n.Value
+	if n.Compressed {
+		value, _ = decompress(n.Value)
+	}
+	return &db.Node{
+		Location:  n.Location,
+		Dep:       n.Dep,
+		Key:       strings.ToValidUTF8(n.Key, "\uFFFD"),
+		Value:     strings.ToValidUTF8(string(value), "\uFFFD"),
+		Children:  n.Children,
+		Created:   n.Created,
+		Deleted:   n.Deleted,
+		Expires:   n.Expires,
+		Timestamp: n.Timestamp,
+	}
+}
+
+func (n *Node) ProtoV2() *dbv2.Node {
+	return &dbv2.Node{
+		Location:   n.Location,
+		Dep:        n.Dep,
+		Key:        []byte(n.Key),
+		Value:      n.Value,
+		Children:   n.Children,
+		Deleted:    n.Deleted,
+		Metadata:   n.Metadata(),
+		Next:       n.Next,
+		Chunk:      n.Chunk,
+		Compressed: n.Compressed,
+		Checksum:   n.Checksum,
+	}
+}
+
+func (n *Node) Metadata() *dbv2.Metadata {
+	return &dbv2.Metadata{
+		ContentType: n.ContentType,
+		Size:        uint64(n.Size()),
+		Created:     n.Created,
+		Expires:     n.Expires,
+		Timestamp:   n.Timestamp,
+	}
+}
+
+// Commit time, derived from creation time for versions without timestamp
+func (n *Node) CommitTime() uint64 {
+	if n.Timestamp != 0 {
+		return n.Timestamp
+	}
+	return uint64(n.Created/int64(time.Millisecond)) << logicalBits
+}
+
+// Fixed size of a node without its contents
+var nodeOverhead = int64(unsafe.Sizeof(Node{}))
+
+// Estimated bytes used by the node (chunks of the value are separate nodes)
+func (n *Node) Bytes() int64 {
+	return nodeOverhead + int64(len(n.Key)+len(n.Value)+len(n.ContentType)+8*len(n.Children))
+}
+
+func (n *Node) Chunked() bool {
+	return n.Next != 0 && !n.Chunk
+}
+
+// Size of the whole uncompressed value
+func (n *Node) Size() int64 {
+	if n.Chunked() || n.Compressed {
+		return n.Length
+	}
+	return int64(len(n.Value))
+}
+
+func (n *Node) Expired(now time.Time) bool {
+	return n.Expires > 0 && n.Expires <= now.UnixNano()
+}
+
+// Whether both nodes are the same version regardless of children and compression
+func (n *Node) SameContent(o *Node) bool {
+	if n.Key != o.Key || n.Dep != o.Dep || n.Created != o.Created ||
+		n.Deleted != o.Deleted || n.Expires != o.Expires || n.ContentType != o.ContentType ||
+		n.Next != o.Next || n.Chunk != o.Chunk || n.Size() != o.Size() {
+		return false
+	}
+	a, b := *n, *o
+	if a.Decompress() != nil || b.Decompress() != nil {
+		return false
+	}
+	return bytes.Equal(a.Value, b.Value)
+}
+
+// Whether a node sent through the v1 API is the same version as n,
+// whose value is not preserved by the v1 API if it is not valid UTF-8
+func (n *Node) SameContentV1(o *db.Node) bool {
+	p := n.Proto()
+	return p.Key == o.Key && p.Dep == o.Dep && p.Created == o.Created &&
+		p.Deleted == o.Deleted && p.Expires == o.Expires && p.Value == o.Value
+}
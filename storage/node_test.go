@@ -0,0 +1,15 @@
+package storage
+
+import "testing"
+
+func TestSameContentV1(t *testing.T) {
+	node := Node{Location: 1, Key: "key", Value: []byte{0xff, 0xfe}, Created: 1}
+	v1 := node.Proto()
+	if !node.SameContentV1(v1) {
+		t.Fatalf("Binary value read through the v1 API is a different version")
+	}
+	v1.Value = "other"
+	if node.SameContentV1(v1) {
+		t.Fatalf("Different value is the same version")
+	}
+}
@@ -0,0 +1,185 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const snapshotFile = "snapshot.bin"
+
+// Bump when the layout of snapshot changes
+const snapshotVersion = 1
+
+type snapshot struct {
+	Version             int
+	Nodes               []Node
+	MemLocation         map[uint64]int
+	Size                int
+	MergeFunctions      map[uint64]string
+	GlobalMergeFunction string
+	// Sequence number of the last log record included
+	Seq uint64
+}
+
+func writeSnapshot(dir string, snap *snapshot) error {
+	path := filepath.Join(dir, snapshotFile)
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	if err := gob.NewEncoder(writer).Encode(snap); err != nil {
+		file.Close()
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// Atomically replace the old snapshot
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Return nil if there is no snapshot
+func readSnapshot(dir string) (*snapshot, error) {
+	file, err := os.Open(filepath.Join(dir, snapshotFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snap snapshot
+	if err := gob.NewDecoder(bufio.NewReader(file)).Decode(&snap); err != nil {
+		return nil, err
+	}
+	if snap.Version != snapshotVersion {
+		return nil, fmt.Errorf("Unsupported snapshot version %d", snap.Version)
+	}
+	return &snap, nil
+}
+
+// Write the whole store to disk and truncate the log
+func (s *Store) Snapshot() error {
+	if s.wal == nil {
+		return fmt.Errorf("Persistence is disabled")
+	}
+
+	s.lockAll()
+	defer s.unlockAll()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.wal.lock.Lock()
+	defer s.wal.lock.Unlock()
+
+	nodes, memLocation := s.Nodes, s.MemLocation
+	if len(s.spilled) > 0 {
+		// Spilled nodes are lost on restart unless they are in the snapshot
+		nodes = append([]Node(nil), s.Nodes...)
+		memLocation = make(map[uint64]int, len(s.MemLocation)+len(s.spilled))
+		for loc, memLoc := range s.MemLocation {
+			memLocation[loc] = memLoc
+		}
+		for _, node := range s.rangeSpilled(0, math.MaxUint32) {
+			memLocation[node.Location] = len(nodes)
+			nodes = append(nodes, node)
+		}
+	}
+
+	err := writeSnapshot(s.dir, &snapshot{
+		Version:             snapshotVersion,
+		Nodes:               nodes,
+		MemLocation:         memLocation,
+		Size:                s.Size,
+		MergeFunctions:      s.mergeFunctions,
+		GlobalMergeFunction: s.globalMergeFunction,
+		Seq:                 s.wal.seq,
+	})
+	if err != nil {
+		return err
+	}
+	// All records before are in the snapshot now
+	return s.wal.reset()
+}
+
+// Whether the log has grown enough to take a snapshot
+func (s *Store) NeedSnapshot() bool {
+	if s.wal == nil || s.snapshotInterval <= 0 {
+		return false
+	}
+	s.wal.lock.Lock()
+	defer s.wal.lock.Unlock()
+	return s.wal.count >= s.snapshotInterval
+}
+
+func (s *Store) SetMergeFunction(loc uint64, name string) error {
+	if s.wal == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.wal.append(&walRecord{Op: opMergeFunction, Location: loc, Name: name}); err != nil {
+		return err
+	}
+	s.setMergeFunction(loc, name)
+	return nil
+}
+
+// Lock must be held
+func (s *Store) setMergeFunction(loc uint64, name string) {
+	if len(name) == 0 {
+		delete(s.mergeFunctions, loc)
+		return
+	}
+	if s.mergeFunctions == nil {
+		s.mergeFunctions = make(map[uint64]string)
+	}
+	s.mergeFunctions[loc] = name
+}
+
+func (s *Store) SetGlobalMergeFunction(name string) error {
+	if s.wal == nil {
+		return nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err := s.wal.append(&walRecord{Op: opGlobalMergeFunction, Name: name}); err != nil {
+		return err
+	}
+	s.globalMergeFunction = name
+	return nil
+}
+
+// Merge functions restored from the last snapshot and log
+func (s *Store) MergeFunctions() (map[uint64]string, string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	functions := make(map[uint64]string, len(s.mergeFunctions))
+	for loc, name := range s.mergeFunctions {
+		functions[loc] = name
+	}
+	return functions, s.globalMergeFunction
+}
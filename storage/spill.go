@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/DCsunset/openwhisk-grpc/utils"
+)
+
+const segmentFile = "segment.dat"
+
+// Estimated bytes of an index entry kept in memory for a spilled node
+const spillOverhead = 48
+
+// Position of a spilled node in the segment file
+type spillEntry struct {
+	offset int64
+	length int32
+}
+
+// Reads served by each tier
+type TierStats struct {
+	MemoryHits int64
+	DiskHits   int64
+	Spilled    int // Nodes currently on disk
+}
+
+// Spilled nodes are only a cache of memory.
+// The segment is recreated on start since snapshots and log contain all nodes.
+func (s *Store) openSegment(dir string) error {
+	if len(dir) == 0 {
+		return fmt.Errorf("Spilling requires a data directory")
+	}
+	file, err := os.OpenFile(filepath.Join(dir, segmentFile), os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	s.segment = file
+	s.spilled = make(map[uint64]spillEntry)
+	return nil
+}
+
+func (s *Store) readSpilled(entry spillEntry) (*Node, error) {
+	data := make([]byte, entry.length)
+	if _, err := s.segment.ReadAt(data, entry.offset); err != nil {
+		return nil, err
+	}
+	return decodeNode(data), nil
+}
+
+// Move nodes not read since before to the segment file
+// and return the number of nodes spilled
+func (s *Store) Spill(before time.Time) (int, error) {
+	if s.segment == nil {
+		return 0, nil
+	}
+	s.lockAll()
+	defer s.unlockAll()
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	deadline := before.UnixNano()
+	var buf bytes.Buffer
+	var locations []uint64
+	entries := make(map[uint64]spillEntry)
+	for i := 1; i < len(s.Nodes); i += 1 {
+		node := &s.Nodes[i]
+		if !s.valid(i) || node.read >= deadline {
+			continue
+		}
+		data := encodeNode(node)
+		entries[node.Location] = spillEntry{
+			offset: s.segmentEnd + int64(buf.Len()),
+			length: int32(len(data)),
+		}
+		buf.Write(data)
+		locations = append(locations, node.Location)
+	}
+	if len(locations) == 0 {
+		return 0, nil
+	}
+	if _, err := s.segment.WriteAt(buf.Bytes(), s.segmentEnd); err != nil {
+		return 0, err
+	}
+	s.segmentEnd += int64(buf.Len())
+
+	for _, loc := range locations {
+		memLoc := s.MemLocation[loc]
+		atomic.AddInt64(&s.bytes, spillOverhead-s.usage(&s.Nodes[memLoc]))
+		s.release(&s.Nodes[memLoc])
+		s.Nodes[memLoc] = Node{}
+		delete(s.MemLocation, loc)
+		s.free = append(s.free, memLoc)
+		s.spilled[loc] = entries[loc]
+	}
+	return len(locations), s.compactSegment()
+}
+
+// Rewrite the segment once most of it belongs to nodes faulted in or removed
+func (s *Store) compactSegment() error {
+	var live int64
+	for _, entry := range s.spilled {
+		live += int64(entry.length)
+	}
+	if live*2 >= s.segmentEnd {
+		return nil
+	}
+
+	path := filepath.Join(s.dir, segmentFile)
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	entries := make(map[uint64]spillEntry, len(s.spilled))
+	for loc, entry := range s.spilled {
+		data := make([]byte, entry.length)
+		if _, err := s.segment.ReadAt(data, entry.offset); err != nil {
+			file.Close()
+			return err
+		}
+		entries[loc] = spillEntry{offset: int64(buf.Len()), length: entry.length}
+		buf.Write(data)
+	}
+	if _, err := file.Write(buf.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		file.Close()
+		return err
+	}
+	s.segment.Close()
+	s.segment = file
+	s.segmentEnd = int64(buf.Len())
+	s.spilled = entries
+	return nil
+}
+
+// Load a spilled node back into memory and return whether it was spilled.
+// The stripe of loc must be held.
+func (s *Store) fault(loc uint64) bool {
+	if s.segment == nil {
+		return false
+	}
+	s.lock.RLock()
+	entry, ok := s.spilled[loc]
+	var node *Node
+	var err error
+	if ok {
+		node, err = s.readSpilled(entry)
+	}
+	s.lock.RUnlock()
+	if !ok {
+		return false
+	}
+	if err != nil {
+		// Segment is only a copy of data in snapshot and log
+		log.Fatalln(err)
+	}
+
+	s.lock.Lock()
+	s.insert(*node)
+	s.lock.Unlock()
+	return true
+}
+
+// Spilled nodes in [left, right] (lock must be held)
+func (s *Store) rangeSpilled(left, right uint32) []Node {
+	var nodes []Node
+	for loc, entry := range s.spilled {
+		keyHash := utils.KeyHash(loc)
+		if keyHash < left || keyHash > right {
+			continue
+		}
+		node, err := s.readSpilled(entry)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		nodes = append(nodes, *node)
+	}
+	return nodes
+}
+
+func (s *Store) TierStats() TierStats {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return TierStats{
+		MemoryHits: atomic.LoadInt64(&s.memoryHits),
+		DiskHits:   atomic.LoadInt64(&s.diskHits),
+		Spilled:    len(s.spilled),
+	}
+}
@@ -1,83 +1,274 @@
 package storage
 
 import (
+	"crypto/sha256"
 	"fmt"
-	"math"
-	"math/rand"
+	"log"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/DCsunset/openwhisk-grpc/db"
 	"github.com/DCsunset/openwhisk-grpc/utils"
 )
 
-type Node struct {
-	Location uint64 // The location of the key
-	Dep      uint64
-	Children []uint64
-	Key      string
-	Value    string
+type Options struct {
+	// Storage engine: memory or disk
+	Engine string `json:"engine"`
+	// Directory to persist data (empty to keep everything in memory)
+	Dir string `json:"dir"`
+	// Fsync policy: always, batch or none
+	Sync string `json:"sync"`
+	// Interval in ms between fsyncs for batch policy
+	SyncInterval int `json:"syncInterval"`
+	// Number of log records before taking a snapshot (0 to disable)
+	SnapshotInterval int `json:"snapshotInterval"`
+	// Size in bytes of chunks for values written by streams
+	ChunkSize int `json:"chunkSize"`
+	// Compress values not smaller than this size in bytes (0 to disable)
+	CompressThreshold int `json:"compressThreshold"`
+	// Store identical values once (memory engine only)
+	Dedup bool `json:"dedup"`
+	// Move nodes not read for this many seconds to disk (0 to disable, memory engine only)
+	SpillAfter int `json:"spillAfter"`
 }
 
+// Number of locks for node contents
+const stripes = 64
+
 type Store struct {
 	Nodes []Node // all nodes
 	// Map hash locations to memory locations
 	MemLocation map[uint64]int
-	lock        sync.RWMutex
-	Size        int // Size of valid nodes
+	// Guard Nodes, MemLocation, Size and free
+	lock sync.RWMutex
+	// Guard contents of nodes and order writes to the same location.
+	// Must be acquired before lock.
+	stripes [stripes]sync.Mutex
+	Size    int   // Size of valid nodes
+	bytes   int64 // Bytes used by valid nodes and blobs (atomic)
+	free    []int // Slots of removed nodes to reuse
+	wal     *wal
+	dir     string
+
+	snapshotInterval  int
+	compressThreshold int
+	// Merge functions in the snapshot and log, guarded by lock
+	mergeFunctions      map[uint64]string
+	globalMergeFunction string
+
+	// Shared values by hash if dedup is enabled
+	dedup bool
+	blobs map[[sha256.Size]byte]*blob
+
+	// Cold nodes moved to segment file if spilling is enabled
+	segment    *os.File
+	segmentEnd int64
+	spilled    map[uint64]spillEntry
+	memoryHits int64 // atomic
+	diskHits   int64 // atomic
 }
 
-func (s *Store) Init() {
+func (s *Store) Init(opts Options) error {
+	s.compressThreshold = opts.CompressThreshold
+	s.dedup = opts.Dedup
+	s.blobs = make(map[[sha256.Size]byte]*blob)
 	if len(s.Nodes) == 0 {
 		// Create a root and map first
 		s.MemLocation = make(map[uint64]int)
-		root := Node{
-			Dep:      math.MaxUint64,
-			Location: 0,
-			Key:      "",
-		}
-		s.Nodes = append(s.Nodes, root)
+		s.Nodes = append(s.Nodes, rootNode())
 		s.MemLocation[0] = 0
 	}
+
+	if len(opts.Dir) == 0 {
+		if opts.SpillAfter > 0 {
+			return fmt.Errorf("Spilling requires a data directory")
+		}
+		return nil
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return err
+	}
+	s.dir = opts.Dir
+	if opts.SpillAfter > 0 {
+		if err := s.openSegment(opts.Dir); err != nil {
+			return err
+		}
+	}
+	s.snapshotInterval = opts.SnapshotInterval
+
+	snap, err := readSnapshot(opts.Dir)
+	if err != nil {
+		return err
+	}
+	var after uint64
+	if snap != nil {
+		after = snap.Seq
+		s.Nodes = snap.Nodes
+		s.MemLocation = snap.MemLocation
+		s.Size = snap.Size
+		s.mergeFunctions = snap.MergeFunctions
+		s.globalMergeFunction = snap.GlobalMergeFunction
+		s.collectFree()
+		// Share values again since snapshot stores a copy for each node
+		now := time.Now().UnixNano()
+		for _, memLoc := range s.MemLocation {
+			s.Nodes[memLoc].read = now
+			s.acquire(&s.Nodes[memLoc])
+			s.bytes += s.usage(&s.Nodes[memLoc])
+		}
+	}
+
+	w, err := openWal(opts)
+	if err != nil {
+		return err
+	}
+	// Replay the tail of log after snapshot.
+	// The log is not reset yet if the process stopped right after writing the snapshot.
+	err = w.replay(after, func(r *walRecord) {
+		switch r.Op {
+		case opNew:
+			s.insert(r.Node)
+		case opAddChild:
+			s.appendChild(r.Location, r.Children[0])
+		case opSetChildren:
+			s.setChildren(r.Location, r.Children)
+		case opRemove:
+			s.remove(r.Location)
+		case opMergeFunction:
+			s.setMergeFunction(r.Location, r.Name)
+		case opGlobalMergeFunction:
+			s.globalMergeFunction = r.Name
+		}
+	})
+	if err != nil {
+		w.close()
+		return err
+	}
+	s.wal = w
+	return nil
 }
 
-func (s *Store) newNode(location uint64, dep uint64, key string, value string) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+func (s *Store) Close() error {
+	if s.segment != nil {
+		s.segment.Close()
+	}
+	if s.wal == nil {
+		return nil
+	}
+	return s.wal.close()
+}
 
-	s.Size += 1
-	node := Node{
-		Location: location,
-		Dep:      dep,
-		Key:      key,
-		Children: nil,
-		Value:    value,
+// Write the record to log before applying it
+func (s *Store) log(r *walRecord) {
+	if s.wal == nil {
+		return
+	}
+	if err := s.wal.append(r); err != nil {
+		log.Fatalln(err)
 	}
+}
 
-	s.Nodes = append(s.Nodes, node)
-	memLoc := len(s.Nodes) - 1
+func (s *Store) stripe(location uint64) *sync.Mutex {
+	return &s.stripes[(location^(location>>32))%stripes]
+}
 
-	s.MemLocation[location] = memLoc
+func (s *Store) lockAll() {
+	for i := range s.stripes {
+		s.stripes[i].Lock()
+	}
 }
 
-func (s *Store) Get(key string, loc uint64) (string, error) {
-	// FIXME: Similuate disk
-	time.Sleep(time.Millisecond * 10)
+func (s *Store) unlockAll() {
+	for i := range s.stripes {
+		s.stripes[i].Unlock()
+	}
+}
 
-	var node *Node
-	node = s.GetNode(loc)
+func (s *Store) PutNode(node Node) {
+	stripe := s.stripe(node.Location)
+	stripe.Lock()
+	defer stripe.Unlock()
 
-	// Find till root
-	for {
-		if node.Key == key {
-			return node.Value, nil
-		}
-		if node.Dep == math.MaxUint64 {
-			break
+	s.write(node)
+}
+
+func (s *Store) PutNewNode(node Node) bool {
+	stripe := s.stripe(node.Location)
+	stripe.Lock()
+	defer stripe.Unlock()
+
+	// No one else can take the location while the stripe is held
+	s.lock.RLock()
+	_, taken := s.MemLocation[node.Location]
+	_, spilled := s.spilled[node.Location]
+	s.lock.RUnlock()
+	if taken || spilled {
+		return false
+	}
+	s.write(node)
+	return true
+}
+
+// Log and insert a node (stripe must be held)
+func (s *Store) write(node Node) {
+	compressNode(&node, s.compressThreshold)
+	node.Checksum = node.ComputeChecksum()
+	s.log(&walRecord{Op: opNew, Node: node})
+	s.lock.Lock()
+	s.insert(node)
+	s.lock.Unlock()
+}
+
+func (s *Store) insert(node Node) {
+	if _, ok := s.spilled[node.Location]; ok {
+		// Replace spilled node
+		delete(s.spilled, node.Location)
+		s.Size -= 1
+		atomic.AddInt64(&s.bytes, -spillOverhead)
+	}
+	node.read = time.Now().UnixNano()
+	s.acquire(&node)
+	atomic.AddInt64(&s.bytes, s.usage(&node))
+	if memLoc, ok := s.MemLocation[node.Location]; ok {
+		// Replace existing node
+		atomic.AddInt64(&s.bytes, -s.usage(&s.Nodes[memLoc]))
+		s.release(&s.Nodes[memLoc])
+		s.Nodes[memLoc] = node
+		return
+	}
+	s.Size += 1
+	var memLoc int
+	if l := len(s.free); l > 0 {
+		memLoc = s.free[l-1]
+		s.free = s.free[:l-1]
+		s.Nodes[memLoc] = node
+	} else {
+		s.Nodes = append(s.Nodes, node)
+		memLoc = len(s.Nodes) - 1
+	}
+
+	s.MemLocation[node.Location] = memLoc
+}
+
+// Rebuild the free list from slots not mapped by any location
+func (s *Store) collectFree() {
+	used := make([]bool, len(s.Nodes))
+	for _, memLoc := range s.MemLocation {
+		used[memLoc] = true
+	}
+	s.free = nil
+	for i, ok := range used {
+		if !ok {
+			s.free = append(s.free, i)
 		}
-		node = s.GetNode(node.Dep)
 	}
-	return "", fmt.Errorf("Key %s not found", key)
+}
+
+// Whether the slot holds a valid node
+func (s *Store) valid(memLoc int) bool {
+	loc, ok := s.MemLocation[s.Nodes[memLoc].Location]
+	return ok && loc == memLoc
 }
 
 type Data struct {
@@ -87,35 +278,70 @@ type Data struct {
 }
 
 func (self *Store) AddChild(location uint64, child uint64) *Node {
-	node := self.GetNode(location)
-	node.Children = append(node.Children, child)
-	return node
+	stripe := self.stripe(location)
+	stripe.Lock()
+	defer stripe.Unlock()
+
+	self.fault(location)
+	self.log(&walRecord{Op: opAddChild, Location: location, Children: []uint64{child}})
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+	return copyNode(self.appendChild(location, child))
 }
 
-func (s *Store) Set(key string, value string, dep uint64) uint64 {
-	// FIXME: Similuate disk
-	time.Sleep(time.Millisecond * 10)
+func (self *Store) appendChild(location uint64, child uint64) *Node {
+	node := self.node(location)
+	if node != nil {
+		node.Children = append(node.Children, child)
+		atomic.AddInt64(&self.bytes, 8)
+	}
+	return node
+}
 
-	// Use random number + key hash
-	loc := uint64(rand.Uint32()) + (uint64(utils.Hash2Uint(utils.Hash([]byte(key)))) << 32)
-	s.newNode(loc, dep, key, value)
+// Replace all children of a node
+func (s *Store) SetChildren(location uint64, children []uint64) {
+	stripe := s.stripe(location)
+	stripe.Lock()
+	defer stripe.Unlock()
 
-	return loc
+	s.fault(location)
+	s.log(&walRecord{Op: opSetChildren, Location: location, Children: children})
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	s.setChildren(location, children)
 }
 
-func CreateNode(key, value string, dep uint64) *db.Node {
-	// Use random number + key hash
-	loc := uint64(rand.Uint32()) + (uint64(utils.Hash2Uint(utils.Hash([]byte(key)))) << 32)
-	return &db.Node{
-		Location: loc,
-		Dep:      dep,
-		Key:      key,
-		Value:    value,
-		Children: nil,
+func (s *Store) setChildren(location uint64, children []uint64) {
+	node := s.node(location)
+	if node != nil {
+		atomic.AddInt64(&s.bytes, int64(8*(len(children)-len(node.Children))))
+		node.Children = children
 	}
 }
 
+// Return a copy of the node so that it can be read without locks
 func (s *Store) GetNode(loc uint64) *Node {
+	stripe := s.stripe(loc)
+	stripe.Lock()
+	defer stripe.Unlock()
+	faulted := s.fault(loc)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	node := s.node(loc)
+	if node != nil {
+		// Safe under read lock since the stripe is held
+		node.read = time.Now().UnixNano()
+		if faulted {
+			atomic.AddInt64(&s.diskHits, 1)
+		} else {
+			atomic.AddInt64(&s.memoryHits, 1)
+		}
+	}
+	return copyNode(node)
+}
+
+func (s *Store) node(loc uint64) *Node {
 	memLoc, ok := s.MemLocation[loc]
 	if !ok {
 		return nil
@@ -123,26 +349,91 @@ func (s *Store) GetNode(loc uint64) *Node {
 	return &s.Nodes[memLoc]
 }
 
-func (s *Store) AddNode(node *db.Node) {
-	s.newNode(node.Location, node.Dep, node.Key, node.Value)
+// Children are only appended or replaced so they can be shared
+func copyNode(node *Node) *Node {
+	if node == nil {
+		return nil
+	}
+	n := *node
+	return &n
 }
 
 func (s *Store) RemoveNode(location uint64) {
-	for i, node := range s.Nodes {
-		if node.Location == location {
-			s.Nodes[i] = Node{
-				Key: "",
-			}
-			s.Size -= 1
+	stripe := s.stripe(location)
+	stripe.Lock()
+	defer stripe.Unlock()
+
+	s.log(&walRecord{Op: opRemove, Location: location})
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.remove(location)
+}
+
+func (s *Store) remove(location uint64) {
+	if _, ok := s.spilled[location]; ok {
+		// Space in segment is reclaimed by compaction
+		delete(s.spilled, location)
+		s.Size -= 1
+		atomic.AddInt64(&s.bytes, -spillOverhead)
+		return
+	}
+	memLoc, ok := s.MemLocation[location]
+	// Never remove root
+	if !ok || location == 0 {
+		return
+	}
+	atomic.AddInt64(&s.bytes, -s.usage(&s.Nodes[memLoc]))
+	s.release(&s.Nodes[memLoc])
+	s.Nodes[memLoc] = Node{}
+	delete(s.MemLocation, location)
+	s.free = append(s.free, memLoc)
+	s.Size -= 1
+}
+
+func (s *Store) Range(left, right uint32, fn func(node *Node) bool) {
+	// Copy nodes so that fn runs without locks
+	var nodes []Node
+	s.lockAll()
+	s.lock.RLock()
+	for i := 1; i < len(s.Nodes); i += 1 {
+		if !s.valid(i) {
+			continue
+		}
+		keyHash := utils.KeyHash(s.Nodes[i].Location)
+		if keyHash >= left && keyHash <= right {
+			nodes = append(nodes, s.Nodes[i])
+		}
+	}
+	nodes = append(nodes, s.rangeSpilled(left, right)...)
+	s.lock.RUnlock()
+	s.unlockAll()
+
+	for i := range nodes {
+		if !fn(&nodes[i]) {
 			return
 		}
 	}
 }
 
+func (s *Store) Count() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.Size
+}
+
+func (s *Store) Bytes() int64 {
+	return atomic.LoadInt64(&s.bytes)
+}
+
 func (s *Store) Print() {
+	s.lockAll()
+	defer s.unlockAll()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
 	fmt.Println("Nodes:")
-	for _, node := range s.Nodes {
-		if len(node.Key) > 0 {
+	for i, node := range s.Nodes {
+		if i > 0 && s.valid(i) {
 			fmt.Printf("%s (Dep: %x, Chilren: %s)\n", node.Key, node.Dep, utils.ToString(node.Children))
 		}
 	}
@@ -0,0 +1,331 @@
+package storage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func newTestStore(t testing.TB, engine string) Engine {
+	opts := Options{Engine: engine}
+	if engine == EngineDisk {
+		dir, err := ioutil.TempDir("", "store")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			os.RemoveAll(dir)
+		})
+		opts.Dir = dir
+		opts.Sync = SyncNone
+	}
+	e, err := NewEngine(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestSetRetriesOnCollision(t *testing.T) {
+	e := newTestStore(t, EngineMemory)
+	defer e.Close()
+	// Random parts 1, 1 and 2
+	SetRandomSource(NewRepeatSource([]int64{1 << 31, 1 << 31, 2 << 31}))
+	defer SetRandomSource(rand.NewSource(seed()))
+
+	first := Set(e, Node{Key: "key", Value: []byte("1")}, 0)
+	second := Set(e, Node{Key: "key", Value: []byte("2")}, 0)
+	if first == second {
+		t.Fatalf("Both versions are at location %x", first)
+	}
+	if second != first+1 {
+		t.Fatalf("Collision at %x is not retried with the next random number", first)
+	}
+	for loc, value := range map[uint64]string{first: "1", second: "2"} {
+		if node := e.GetNode(loc); node == nil || string(node.Value) != value {
+			t.Fatalf("Version %s is lost", value)
+		}
+	}
+}
+
+// Run with -race
+func TestStoreConcurrentAccess(t *testing.T) {
+	for _, engine := range []string{EngineMemory, EngineDisk} {
+		t.Run(engine, func(t *testing.T) {
+			testConcurrentAccess(t, newTestStore(t, engine))
+		})
+	}
+}
+
+func testConcurrentAccess(t *testing.T, e Engine) {
+	defer e.Close()
+
+	const writers = 8
+	const versions = 200
+	before := e.Count()
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", w)
+			var locs []uint64
+			for i := 0; i < versions; i++ {
+				dep := uint64(0)
+				if len(locs) > 0 {
+					dep = locs[len(locs)-1]
+				}
+				loc := Set(e, Node{Key: key, Value: []byte(strconv.Itoa(i)), Dep: dep}, 0)
+				if dep != 0 && e.AddChild(dep, loc) == nil {
+					t.Errorf("Parent %x of %s is missing", dep, key)
+					return
+				}
+				node := e.GetNode(loc)
+				if node == nil || node.Key != key || string(node.Value) != strconv.Itoa(i) {
+					t.Errorf("Version %d of %s is not readable", i, key)
+					return
+				}
+				locs = append(locs, loc)
+
+				// Remove older versions while others are added
+				if len(locs) > 2 {
+					e.RemoveNode(locs[0])
+					if e.GetNode(locs[0]) != nil {
+						t.Errorf("Removed location %x is still readable", locs[0])
+						return
+					}
+					locs = locs[1:]
+				}
+			}
+		}(w)
+	}
+
+	// Iterate like splits while nodes are added and removed
+	var iterations sync.WaitGroup
+	iterations.Add(1)
+	go func() {
+		defer iterations.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			e.Range(0, math.MaxUint32, func(node *Node) bool {
+				if len(node.Key) == 0 && node.Location != 0 {
+					t.Errorf("Empty node at location %x", node.Location)
+					return false
+				}
+				return true
+			})
+			e.Count()
+			e.Bytes()
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	iterations.Wait()
+
+	// The last 2 versions of each key
+	if count := e.Count(); count != before+2*writers {
+		t.Fatalf("Store has %d nodes instead of %d", count, before+2*writers)
+	}
+}
+
+// Store with the locations of the nodes moved out by a split of the whole range in half
+func splitStore(b *testing.B, moved int) (Engine, []uint64) {
+	e := newTestStore(b, EngineMemory)
+	value := make([]byte, 100)
+	var locs []uint64
+	for i := 0; len(locs) < moved; i++ {
+		loc := Set(e, Node{Key: strconv.Itoa(i), Value: value}, 0)
+		if loc>>32 > math.MaxUint32/2 {
+			locs = append(locs, loc)
+		}
+	}
+	return e, locs
+}
+
+func BenchmarkRemoveAfterSplit(b *testing.B) {
+	e, moved := splitStore(b, b.N)
+	defer e.Close()
+
+	b.ResetTimer()
+	for _, loc := range moved {
+		e.RemoveNode(loc)
+	}
+}
+
+// Nodes added after a split reuse the slots of the removed ones
+func BenchmarkSetAfterSplit(b *testing.B) {
+	e, moved := splitStore(b, b.N)
+	defer e.Close()
+	for _, loc := range moved {
+		e.RemoveNode(loc)
+	}
+	value := make([]byte, 100)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Set(e, Node{Key: strconv.Itoa(i), Value: value}, 0)
+	}
+}
+
+// Merge functions are restored from the log without a snapshot
+func TestMergeFunctionsSurviveRestart(t *testing.T) {
+	for _, engine := range []string{EngineMemory, EngineDisk} {
+		t.Run(engine, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "store")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+			opts := Options{Engine: engine, Dir: dir}
+
+			e, err := NewEngine(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			snapshotter := e.(Snapshotter)
+			snapshotter.SetMergeFunction(1, "merge")
+			snapshotter.SetMergeFunction(2, "removed")
+			snapshotter.SetMergeFunction(2, "")
+			snapshotter.SetGlobalMergeFunction("global")
+			e.Close()
+
+			e, err = NewEngine(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer e.Close()
+			functions, global := e.(Snapshotter).MergeFunctions()
+			if want := map[uint64]string{1: "merge"}; !reflect.DeepEqual(functions, want) {
+				t.Fatalf("Merge functions are %v instead of %v", functions, want)
+			}
+			if global != "global" {
+				t.Fatalf("Global merge function is %q", global)
+			}
+		})
+	}
+}
+
+// The log is not reset if the process stops right after writing a snapshot
+func TestReplaySkipsRecordsInSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := Options{Dir: dir}
+
+	e, err := NewEngine(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotter := e.(Snapshotter)
+	parent := Set(e, Node{Key: "a", Value: []byte("1")}, 0)
+	if err := snapshotter.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	e.AddChild(parent, 42)
+	log, err := ioutil.ReadFile(filepath.Join(dir, walFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := snapshotter.Snapshot(); err != nil {
+		t.Fatal(err)
+	}
+	e.Close()
+	// Log before it was reset by the second snapshot
+	if err := ioutil.WriteFile(filepath.Join(dir, walFile), log, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	e, err = NewEngine(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if children := e.GetNode(parent).Children; !reflect.DeepEqual(children, []uint64{42}) {
+		t.Fatalf("Children are %v after replaying records in the snapshot", children)
+	}
+	// Records after the snapshot are still replayed
+	e.AddChild(parent, 43)
+	e.Close()
+	e, err = NewEngine(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+	if children := e.GetNode(parent).Children; !reflect.DeepEqual(children, []uint64{42, 43}) {
+		t.Fatalf("Children are %v after restart", children)
+	}
+}
+
+func TestStoreSurvivesRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	opts := Options{Dir: dir}
+	open := func() Engine {
+		e, err := NewEngine(opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+
+	e := open()
+	parent := Set(e, Node{Key: "a", Value: []byte("1")}, 0)
+	child := Set(e, Node{Key: "a", Value: []byte("2"), Dep: parent}, 0)
+	e.AddChild(parent, child)
+	removed := Set(e, Node{Key: "b", Value: []byte("3")}, 0)
+	e.RemoveNode(removed)
+	e.Close()
+
+	// The last record is torn
+	e = open()
+	torn := Set(e, Node{Key: "c", Value: []byte("4")}, 0)
+	e.Close()
+	path := filepath.Join(dir, walFile)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Truncate(path, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+
+	e = open()
+	if node := e.GetNode(parent); node == nil || string(node.Value) != "1" || !reflect.DeepEqual(node.Children, []uint64{child}) {
+		t.Fatalf("Parent is restored as %v", node)
+	}
+	if node := e.GetNode(child); node == nil || string(node.Value) != "2" || node.Dep != parent {
+		t.Fatalf("Child is restored as %v", node)
+	}
+	if e.GetNode(removed) != nil || e.GetNode(torn) != nil {
+		t.Fatalf("Removed or torn node is restored")
+	}
+
+	// Records are appended after the cut tail
+	after := Set(e, Node{Key: "d", Value: []byte("5")}, 0)
+	e.Close()
+	e = open()
+	defer e.Close()
+	if e.GetNode(after) == nil {
+		t.Fatalf("Node logged after the torn record is lost")
+	}
+	if count := e.Count(); count != 3 {
+		t.Fatalf("Store has %d nodes instead of 3", count)
+	}
+}
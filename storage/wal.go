@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Fsync policies of the write-ahead log
+const (
+	SyncAlways = "always" // fsync after every write
+	SyncBatch  = "batch"  // fsync periodically
+	SyncNone   = "none"   // leave it to the OS
+)
+
+const walFile = "wal.log"
+
+const (
+	opNew byte = iota + 1
+	opAddChild
+	opSetChildren
+	opRemove
+	opMergeFunction
+	opGlobalMergeFunction
+)
+
+type walRecord struct {
+	Op       byte
+	Node     Node
+	Location uint64
+	Children []uint64
+	Name     string // Merge function
+	Seq      uint64 // Increasing across resets so that a snapshot knows the records it contains
+}
+
+// Append-only log of all store mutations
+type wal struct {
+	lock   sync.Mutex
+	file   *os.File
+	policy string
+	dirty  bool
+	count  int    // Records since last reset
+	seq    uint64 // Sequence number of the last record
+	done   chan struct{}
+}
+
+func openWal(opts Options) (*wal, error) {
+	file, err := os.OpenFile(filepath.Join(opts.Dir, walFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	w := &wal{
+		file:   file,
+		policy: opts.Sync,
+		done:   make(chan struct{}),
+	}
+	switch w.policy {
+	case "", SyncAlways:
+		w.policy = SyncAlways
+	case SyncBatch:
+		interval := opts.SyncInterval
+		if interval <= 0 {
+			interval = 100
+		}
+		go w.syncLoop(time.Millisecond * time.Duration(interval))
+	case SyncNone:
+	default:
+		file.Close()
+		return nil, fmt.Errorf("Unknown sync policy %s", opts.Sync)
+	}
+	return w, nil
+}
+
+// Read all valid records after sequence number after and truncate a torn tail.
+// Records up to after are already in the snapshot, which is written before the log is reset.
+func (w *wal) replay(after uint64, apply func(r *walRecord)) error {
+	w.seq = after
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(w.file)
+	var offset int64
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			break
+		}
+		size := binary.BigEndian.Uint32(header[:4])
+		sum := binary.BigEndian.Uint32(header[4:])
+		data := make([]byte, size)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(data) != sum {
+			break
+		}
+		var r walRecord
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&r); err != nil {
+			break
+		}
+		// Records logged without sequence numbers are always after the snapshot
+		if r.Seq == 0 || r.Seq > after {
+			apply(&r)
+		}
+		if r.Seq > w.seq {
+			w.seq = r.Seq
+		}
+		offset += int64(len(header)) + int64(size)
+		w.count += 1
+	}
+
+	if err := w.file.Truncate(offset); err != nil {
+		return err
+	}
+	_, err := w.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (w *wal) append(r *walRecord) error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	r.Seq = w.seq + 1
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 8))
+	if err := gob.NewEncoder(&buf).Encode(r); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[:4], uint32(len(data)-8))
+	binary.BigEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(data[8:]))
+
+	if _, err := w.file.Write(data); err != nil {
+		return err
+	}
+	w.seq = r.Seq
+	w.count += 1
+	if w.policy == SyncAlways {
+		return w.file.Sync()
+	}
+	w.dirty = true
+	return nil
+}
+
+// Discard all records but keep counting sequence numbers (lock must be held)
+func (w *wal) reset() error {
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.count = 0
+	w.dirty = false
+	return w.file.Sync()
+}
+
+func (w *wal) syncLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.lock.Lock()
+			if w.dirty {
+				w.file.Sync()
+				w.dirty = false
+			}
+			w.lock.Unlock()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *wal) close() error {
+	close(w.done)
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if err := w.file.Sync(); err != nil {
+		return err
+	}
+	return w.file.Close()
+}